@@ -0,0 +1,52 @@
+package jwt
+
+import "sync"
+
+// ParseBatch parses tokens concurrently using up to concurrency goroutines, e.g. to verify a large
+// number of tokens during a migration. It returns a ParseResult for each token, in the order of
+// tokens, with Line set to the position of the token, starting at 1. A concurrency below 1 is
+// treated as 1.
+func ParseBatch(tokens []string, keyFunc Keyfunc, concurrency int, options ...ParserOption) []ParseResult {
+	return NewParser(options...).ParseBatch(tokens, keyFunc, concurrency)
+}
+
+// ParseBatch is like the package level ParseBatch, using a copy of this parser for each token.
+// Timings are not recorded, even if the parser uses WithTimings, since the goroutines would
+// overwrite each other's measurements. keyFunc and the observer of WithValidationObserver are
+// called concurrently and must therefore be safe for concurrent use.
+func (p *Parser) ParseBatch(tokens []string, keyFunc Keyfunc, concurrency int) []ParseResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(tokens) {
+		concurrency = len(tokens)
+	}
+
+	// The copy shares the configuration of p, but not its timings, which are written on each parse
+	batch := *p
+	batch.timings = nil
+
+	results := make([]ParseResult, len(tokens))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			// Each goroutine only writes the results of the indexes it received
+			for i := range indexes {
+				token, err := batch.Parse(tokens[i], keyFunc)
+				results[i] = ParseResult{Line: i + 1, Token: token, Err: err}
+			}
+		}()
+	}
+
+	for i := range tokens {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,91 @@
+package jwt_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestParseBatch(t *testing.T) {
+	var tokens []string
+	for i := 0; i < 20; i++ {
+		switch i % 4 {
+		case 1:
+			tokens = append(tokens, "not a token")
+		case 2:
+			tokens = append(tokens, signToken(jwt.MapClaims{"exp": 1}, jwt.SigningMethodRS256))
+		default:
+			tokens = append(tokens, signToken(jwt.MapClaims{"sub": fmt.Sprint(i)}, jwt.SigningMethodRS256))
+		}
+	}
+
+	for _, concurrency := range []int{0, 1, 4, 100} {
+		t.Run(fmt.Sprint(concurrency), func(t *testing.T) {
+			results := jwt.ParseBatch(tokens, defaultKeyFunc, concurrency, jwt.WithValidMethods([]string{"RS256"}))
+			if len(results) != len(tokens) {
+				t.Fatalf("ParseBatch() returned %d results, want %d", len(results), len(tokens))
+			}
+
+			for i, res := range results {
+				if res.Line != i+1 {
+					t.Errorf("result %d: Line = %d, want %d", i, res.Line, i+1)
+				}
+				switch i % 4 {
+				case 1:
+					if !errors.Is(res.Err, jwt.ErrTokenMalformed) {
+						t.Errorf("result %d: error = %v, want %v", i, res.Err, jwt.ErrTokenMalformed)
+					}
+				case 2:
+					if !errors.Is(res.Err, jwt.ErrTokenExpired) {
+						t.Errorf("result %d: error = %v, want %v", i, res.Err, jwt.ErrTokenExpired)
+					}
+				default:
+					if res.Err != nil {
+						t.Errorf("result %d: unexpected error = %v", i, res.Err)
+					} else if sub := res.Token.Claims.(jwt.MapClaims)["sub"]; sub != fmt.Sprint(i) {
+						t.Errorf("result %d: sub = %v, want %d", i, sub, i)
+					}
+				}
+			}
+		})
+	}
+
+	if results := jwt.ParseBatch(nil, defaultKeyFunc, 4); len(results) != 0 {
+		t.Errorf("ParseBatch() returned %d results, want 0", len(results))
+	}
+}
+
+func TestParseBatch_Timings(t *testing.T) {
+	var tokens []string
+	for i := 0; i < 20; i++ {
+		tokens = append(tokens, signToken(jwt.MapClaims{"sub": fmt.Sprint(i)}, jwt.SigningMethodRS256))
+	}
+
+	var (
+		mu     sync.Mutex
+		checks int
+	)
+	observer := func(check string, ok bool, err error) {
+		mu.Lock()
+		checks++
+		mu.Unlock()
+	}
+
+	var timings jwt.ParseTimings
+	results := jwt.ParseBatch(tokens, defaultKeyFunc, 4, jwt.WithTimings(&timings), jwt.WithValidationObserver(observer))
+	for i, res := range results {
+		if res.Err != nil {
+			t.Errorf("result %d: unexpected error = %v", i, res.Err)
+		}
+	}
+
+	if timings != (jwt.ParseTimings{}) {
+		t.Errorf("ParseBatch() recorded timings = %+v, want none", timings)
+	}
+	if checks == 0 {
+		t.Error("ParseBatch() did not call the observer")
+	}
+}
@@ -1,8 +1,15 @@
 package jwt
 
 import (
+	"bytes"
+	"crypto/rand"
 	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -44,6 +51,167 @@ type RegisteredClaims struct {
 	ID string `json:"jti,omitempty"`
 }
 
+// NewJTI returns a new random identifier suitable for the `jti` (JWT ID) claim. It consists of
+// 128 bits read from crypto/rand, encoded as base64url without padding, so that collisions are
+// practically impossible. NewJTI panics if the secure random number generator fails.
+func NewJTI() string {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		panic(fmt.Sprintf("jwt: could not generate jti: %v", err))
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ClaimsEqual reports whether a and b contain the same claims, regardless of their concrete types.
+// Both are compared in their JSON representation, so numbers are equal if they have the same value,
+// e.g. a float64 and a json.Number, and a single "aud" string equals a list with only that string.
+// Claims that cannot be marshalled to a JSON object are never equal.
+func ClaimsEqual(a, b Claims) bool {
+	ma, err := normalizedClaims(a)
+	if err != nil {
+		return false
+	}
+
+	mb, err := normalizedClaims(b)
+	if err != nil {
+		return false
+	}
+
+	return jsonValueEqual(ma, mb)
+}
+
+// claimsMatchJSON reports whether claims are marshalled to the same JSON object as raw, compared
+// like ClaimsEqual.
+func claimsMatchJSON(claims Claims, raw []byte) bool {
+	m, err := normalizedClaims(claims)
+	if err != nil {
+		return false
+	}
+
+	r, err := normalizedJSON(raw)
+	if err != nil {
+		return false
+	}
+
+	return jsonValueEqual(m, r)
+}
+
+// ClaimNames returns the names of all claims in c, sorted in ascending order. For struct claims,
+// these are the names in their JSON representation, so that e.g. fields omitted because of an
+// omitempty tag are not included. See MapClaims.Keys.
+func ClaimNames(c Claims) ([]string, error) {
+	m, err := toMapClaims(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Keys(), nil
+}
+
+// normalizedClaims returns the JSON representation of claims as a map, decoding all numbers
+// as json.Number. An "aud" string is converted to a list.
+func normalizedClaims(claims Claims) (map[string]interface{}, error) {
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizedJSON(b)
+}
+
+// normalizedJSON decodes the JSON object b like normalizedClaims.
+func normalizedJSON(b []byte) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	if aud, ok := m["aud"].(string); ok {
+		m["aud"] = []interface{}{aud}
+	}
+
+	return m, nil
+}
+
+// jsonValueEqual compares two values decoded by encoding/json, comparing numbers by value.
+func jsonValueEqual(a, b interface{}) bool {
+	switch va := a.(type) {
+	case json.Number:
+		vb, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		na, okA := normalizedNumber(string(va))
+		nb, okB := normalizedNumber(string(vb))
+		return okA && okB && na == nb
+	case map[string]interface{}:
+		vb, ok := b.(map[string]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for k, v := range va {
+			w, ok := vb[k]
+			if !ok || !jsonValueEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		vb, ok := b.([]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for i := range va {
+			if !jsonValueEqual(va[i], vb[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		// strings, booleans and null
+		return a == b
+	}
+}
+
+// normalizedNumber returns the JSON number s in the form <digits>e<exponent>, without leading or
+// trailing zeros in digits, so that numbers of equal value have equal forms. Unlike big.Rat, it
+// does not expand the exponent, which would make a claim such as 1e999999 expensive to compare.
+func normalizedNumber(s string) (string, bool) {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	var exp int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return "", false
+		}
+		exp, s = e, s[:i]
+	}
+
+	digits := s
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		digits = s[:i] + s[i+1:]
+		exp -= int64(len(s) - i - 1)
+	}
+
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		// Zero has no sign
+		return "0", true
+	}
+	n := len(digits)
+	digits = strings.TrimRight(digits, "0")
+	exp += int64(n - len(digits))
+
+	return sign + digits + "e" + strconv.FormatInt(exp, 10), true
+}
+
 // Valid validates time based claims "exp, iat, nbf".
 // There is no accounting for clock skew.
 // As well, if any of the above claims are not in the token, it will still
@@ -55,8 +223,7 @@ func (c RegisteredClaims) Valid() error {
 	// The claims below are optional, by default, so if they are set to the
 	// default value in Go, let's not fail the verification for them.
 	if !c.VerifyExpiresAt(now, false) {
-		delta := now.Sub(c.ExpiresAt.Time)
-		vErr.Inner = fmt.Errorf("%s by %s", ErrTokenExpired, delta)
+		vErr.Inner = &TokenExpiredError{ExpiresAt: c.ExpiresAt.Time, Now: now, ExpiredBy: now.Sub(c.ExpiresAt.Time)}
 		vErr.Errors |= ValidationErrorExpired
 	}
 
@@ -66,7 +233,7 @@ func (c RegisteredClaims) Valid() error {
 	}
 
 	if !c.VerifyNotBefore(now, false) {
-		vErr.Inner = ErrTokenNotValidYet
+		vErr.Inner = &TokenNotValidYetError{NotBefore: c.NotBefore.Time, Now: now, ValidIn: c.NotBefore.Sub(now)}
 		vErr.Errors |= ValidationErrorNotValidYet
 	}
 
@@ -77,6 +244,26 @@ func (c RegisteredClaims) Valid() error {
 	return vErr
 }
 
+// GetIssuer returns the iss claim.
+func (c RegisteredClaims) GetIssuer() (string, error) {
+	return c.Issuer, nil
+}
+
+// GetSubject returns the sub claim.
+func (c RegisteredClaims) GetSubject() (string, error) {
+	return c.Subject, nil
+}
+
+// GetAudience returns the aud claim.
+func (c RegisteredClaims) GetAudience() (ClaimStrings, error) {
+	return c.Audience, nil
+}
+
+// GetID returns the jti claim.
+func (c RegisteredClaims) GetID() (string, error) {
+	return c.ID, nil
+}
+
 // VerifyAudience compares the aud claim against cmp.
 // If required is false, this method will return true if the value matches or is unset
 func (c *RegisteredClaims) VerifyAudience(cmp string, req bool) bool {
@@ -119,6 +306,102 @@ func (c *RegisteredClaims) VerifyIssuer(cmp string, req bool) bool {
 	return verifyIss(c.Issuer, cmp, req)
 }
 
+// registered returns c. Since it is promoted to types embedding RegisteredClaims, it gives access
+// to their registered claims, see NewSecureToken.
+func (c *RegisteredClaims) registered() *RegisteredClaims {
+	return c
+}
+
+// OIDCClaims are RegisteredClaims extended by the claims of an OpenID Connect ID token that
+// describe the authentication of the end-user, as referenced at
+// https://openid.net/specs/openid-connect-core-1_0.html#IDToken
+//
+// Like RegisteredClaims, they can be embedded in a user-defined claim type.
+type OIDCClaims struct {
+	RegisteredClaims
+
+	// the `auth_time` (Authentication Time) claim, the time when the end-user authenticated
+	AuthTime *NumericDate `json:"auth_time,omitempty"`
+
+	// the `acr` (Authentication Context Class Reference) claim
+	ACR string `json:"acr,omitempty"`
+
+	// the `amr` (Authentication Methods References) claim, e.g. "pwd" or "otp"
+	AMR []string `json:"amr,omitempty"`
+}
+
+// GetAuthTime returns the auth_time claim.
+func (c OIDCClaims) GetAuthTime() (*NumericDate, error) {
+	return c.AuthTime, nil
+}
+
+// GetACR returns the acr claim.
+func (c OIDCClaims) GetACR() (string, error) {
+	return c.ACR, nil
+}
+
+// GetAMR returns the amr claim.
+func (c OIDCClaims) GetAMR() ([]string, error) {
+	return c.AMR, nil
+}
+
+// PassthroughClaims are RegisteredClaims, which additionally preserve all other claims of a token in
+// their raw JSON form. This allows re-issuing a token without losing claims that are not modelled,
+// e.g. in a gateway. Since PassthroughClaims implement their own JSON marshalling, they should be
+// used as claims type directly rather than being embedded.
+type PassthroughClaims struct {
+	RegisteredClaims
+
+	// Extra contains all claims other than the registered ones, keyed by their name.
+	Extra map[string]json.RawMessage
+}
+
+// registeredClaimNames are the JSON names of the fields of RegisteredClaims.
+var registeredClaimNames = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
+// UnmarshalJSON decodes the registered claims and collects all other claims in Extra.
+func (c *PassthroughClaims) UnmarshalJSON(data []byte) error {
+	var extra map[string]json.RawMessage
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &c.RegisteredClaims); err != nil {
+		return err
+	}
+
+	for _, name := range registeredClaimNames {
+		delete(extra, name)
+	}
+	c.Extra = nil
+	if len(extra) > 0 {
+		c.Extra = extra
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the registered claims together with the claims in Extra. If Extra contains
+// a registered claim, it is ignored in favor of the corresponding field of RegisteredClaims.
+func (c PassthroughClaims) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(c.RegisteredClaims)
+	if err != nil || len(c.Extra) == 0 {
+		return b, err
+	}
+
+	claims := make(map[string]json.RawMessage, len(c.Extra))
+	for k, v := range c.Extra {
+		claims[k] = v
+	}
+	for _, name := range registeredClaimNames {
+		delete(claims, name)
+	}
+	if err = json.Unmarshal(b, &claims); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(claims)
+}
+
 // StandardClaims are a structured version of the JWT Claims Set, as referenced at
 // https://datatracker.ietf.org/doc/html/rfc7519#section-4. They do not follow the
 // specification exactly, since they were based on an earlier draft of the
@@ -148,8 +431,8 @@ func (c StandardClaims) Valid() error {
 	// The claims below are optional, by default, so if they are set to the
 	// default value in Go, let's not fail the verification for them.
 	if !c.VerifyExpiresAt(now, false) {
-		delta := time.Unix(now, 0).Sub(time.Unix(c.ExpiresAt, 0))
-		vErr.Inner = fmt.Errorf("%s by %s", ErrTokenExpired, delta)
+		exp, t := time.Unix(c.ExpiresAt, 0), time.Unix(now, 0)
+		vErr.Inner = &TokenExpiredError{ExpiresAt: exp, Now: t, ExpiredBy: t.Sub(exp)}
 		vErr.Errors |= ValidationErrorExpired
 	}
 
@@ -159,7 +442,8 @@ func (c StandardClaims) Valid() error {
 	}
 
 	if !c.VerifyNotBefore(now, false) {
-		vErr.Inner = ErrTokenNotValidYet
+		nbf, t := time.Unix(c.NotBefore, 0), time.Unix(now, 0)
+		vErr.Inner = &TokenNotValidYetError{NotBefore: nbf, Now: t, ValidIn: nbf.Sub(t)}
 		vErr.Errors |= ValidationErrorNotValidYet
 	}
 
@@ -226,7 +510,8 @@ func verifyAud(aud []string, cmp string, required bool) bool {
 
 	var stringClaims string
 	for _, a := range aud {
-		if subtle.ConstantTimeCompare([]byte(a), []byte(cmp)) != 0 {
+		// an empty audience entry never matches, not even an (accidentally) empty cmp
+		if a != "" && subtle.ConstantTimeCompare([]byte(a), []byte(cmp)) != 0 {
 			result = true
 		}
 		stringClaims = stringClaims + a
@@ -0,0 +1,293 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestNewJTI(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		jti := jwt.NewJTI()
+
+		b, err := base64.RawURLEncoding.DecodeString(jti)
+		if err != nil {
+			t.Fatalf("NewJTI() = %v is not base64url encoded: %v", jti, err)
+		}
+		if len(b) != 16 {
+			t.Fatalf("NewJTI() = %v has %d bytes of entropy, want 16", jti, len(b))
+		}
+		if seen[jti] {
+			t.Fatalf("NewJTI() returned duplicate value %v", jti)
+		}
+		seen[jti] = true
+	}
+}
+
+// scopedClaims embeds RegisteredClaims without implementing any methods of the Claims interface
+// itself.
+type scopedClaims struct {
+	jwt.RegisteredClaims
+	Scope string `json:"scope"`
+}
+
+func TestRegisteredClaims_Embedded(t *testing.T) {
+	keyFunc := func(t *jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	tests := []struct {
+		name   string
+		claims scopedClaims
+		err    error
+	}{
+		{
+			name: "valid",
+			claims: scopedClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Issuer: "test", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+				Scope:            "read",
+			},
+		},
+		{
+			name: "expired",
+			claims: scopedClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Issuer: "test", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour))},
+				Scope:            "read",
+			},
+			err: jwt.ErrTokenExpired,
+		},
+		{
+			name: "not valid yet",
+			claims: scopedClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Issuer: "test", NotBefore: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+				Scope:            "read",
+			},
+			err: jwt.ErrTokenNotValidYet,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tc.claims).SignedString(hmacTestKey)
+			if err != nil {
+				t.Fatalf("SignedString() error = %v", err)
+			}
+
+			claims := &scopedClaims{}
+			token, err := jwt.ParseWithClaims(tokenString, claims, keyFunc, jwt.WithIssuer("test"))
+			if tc.err == nil {
+				if err != nil || !token.Valid {
+					t.Fatalf("ParseWithClaims() unexpected error = %v", err)
+				}
+			} else if !errors.Is(err, tc.err) {
+				t.Fatalf("ParseWithClaims() error = %v, want %v", err, tc.err)
+			}
+
+			if claims.Scope != "read" || claims.Issuer != "test" {
+				t.Errorf("ParseWithClaims() claims = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestClaimsEqual(t *testing.T) {
+	issued := &jwt.RegisteredClaims{
+		Issuer:    "issuer",
+		Audience:  jwt.ClaimStrings{"a"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+	tokenString := signToken(issued, jwt.SigningMethodRS256)
+
+	parsed, err := jwt.Parse(tokenString, defaultKeyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedNumber, err := jwt.Parse(tokenString, defaultKeyFunc, jwt.WithJSONNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		a, b jwt.Claims
+		want bool
+	}{
+		{"issued and parsed", issued, parsed.Claims, true},
+		{"float64 and json.Number", parsed.Claims, parsedNumber.Claims, true},
+		{"integer and float", jwt.MapClaims{"n": 1}, jwt.MapClaims{"n": 1.0}, true},
+		{"single audience string", jwt.MapClaims{"aud": "a"}, jwt.MapClaims{"aud": []string{"a"}}, true},
+		{"nested", jwt.MapClaims{"x": map[string]interface{}{"y": []int{1}}}, jwt.MapClaims{"x": map[string]interface{}{"y": []float64{1}}}, true},
+		{"different value", jwt.MapClaims{"n": 1}, jwt.MapClaims{"n": 2}, false},
+		{"different type", jwt.MapClaims{"n": 1}, jwt.MapClaims{"n": "1"}, false},
+		{"missing claim", jwt.MapClaims{"n": 1, "m": nil}, jwt.MapClaims{"n": 1}, false},
+		{"different audience", issued, jwt.MapClaims{"iss": "issuer", "aud": "b", "exp": issued.ExpiresAt.Unix()}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := jwt.ClaimsEqual(tc.a, tc.b); got != tc.want {
+				t.Errorf("ClaimsEqual() = %v, want %v", got, tc.want)
+			}
+			if got := jwt.ClaimsEqual(tc.b, tc.a); got != tc.want {
+				t.Errorf("ClaimsEqual() reversed = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClaimNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.Claims
+		want   []string
+	}{
+		{"map claims", jwt.MapClaims{"sub": "user", "aud": "a", "exp": 1}, []string{"aud", "exp", "sub"}},
+		{"empty map claims", jwt.MapClaims{}, []string{}},
+		{"registered claims omit empty fields", &jwt.RegisteredClaims{Issuer: "issuer", ID: "id"}, []string{"iss", "jti"}},
+		{"embedded registered claims", &scopedClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: "user"}}, []string{"scope", "sub"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := jwt.ClaimNames(tc.claims)
+			if err != nil {
+				t.Fatalf("ClaimNames() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ClaimNames() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOIDCClaims(t *testing.T) {
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":       "https://accounts.example.com",
+		"sub":       "foo",
+		"exp":       float64(time.Now().Add(time.Hour).Unix()),
+		"auth_time": 1500000000,
+		"acr":       "urn:mace:incommon:iap:silver",
+		"amr":       []string{"pwd", "otp"},
+	}).SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := &jwt.OIDCClaims{}
+	if _, err = jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }); err != nil {
+		t.Fatalf("ParseWithClaims() error = %v", err)
+	}
+
+	if authTime, _ := claims.GetAuthTime(); authTime == nil || !authTime.Equal(time.Unix(1500000000, 0)) {
+		t.Errorf("GetAuthTime() = %v, want %v", authTime, time.Unix(1500000000, 0))
+	}
+	if acr, _ := claims.GetACR(); acr != "urn:mace:incommon:iap:silver" {
+		t.Errorf("GetACR() = %v", acr)
+	}
+	if amr, _ := claims.GetAMR(); !reflect.DeepEqual(amr, []string{"pwd", "otp"}) {
+		t.Errorf("GetAMR() = %v, want [pwd otp]", amr)
+	}
+	if iss, _ := claims.GetIssuer(); iss != "https://accounts.example.com" {
+		t.Errorf("GetIssuer() = %v", iss)
+	}
+
+	// The registered claims are validated as usual
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	if err = claims.Valid(); !errors.Is(err, jwt.ErrTokenExpired) {
+		t.Errorf("Valid() error = %v, want %v", err, jwt.ErrTokenExpired)
+	}
+
+	// Unset claims are omitted
+	b, err := json.Marshal(jwt.OIDCClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: "foo"}})
+	if err != nil || string(b) != `{"sub":"foo"}` {
+		t.Errorf("json.Marshal() = %s, %v", b, err)
+	}
+}
+
+func TestPassthroughClaims(t *testing.T) {
+	keyFunc := func(t *jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+	original := jwt.MapClaims{
+		"iss":    "test",
+		"exp":    float64(time.Now().Add(time.Hour).Unix()),
+		"scope":  "read write",
+		"tenant": map[string]interface{}{"id": 42.0, "name": "acme"},
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, original).SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := &jwt.PassthroughClaims{}
+	if _, err = jwt.ParseWithClaims(tokenString, claims, keyFunc, jwt.WithIssuer("test")); err != nil {
+		t.Fatalf("ParseWithClaims() error = %v", err)
+	}
+	if claims.Issuer != "test" || claims.ExpiresAt == nil {
+		t.Errorf("RegisteredClaims = %+v, want iss and exp", claims.RegisteredClaims)
+	}
+	if len(claims.Extra) != 2 || string(claims.Extra["scope"]) != `"read write"` {
+		t.Errorf("Extra = %s, want scope and tenant", claims.Extra)
+	}
+
+	// Re-issuing preserves all claims, while registered claims are taken from the struct
+	claims.Subject = "user"
+	claims.Extra["iss"] = json.RawMessage(`"ignored"`)
+	tokenString, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	token, err := jwt.Parse(tokenString, keyFunc)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	original["sub"] = "user"
+	if !jwt.ClaimsEqual(token.Claims, original) {
+		t.Errorf("re-issued claims = %v, want %v", token.Claims, original)
+	}
+
+	// Without extra claims, only the registered claims are encoded
+	b, err := json.Marshal(jwt.PassthroughClaims{RegisteredClaims: jwt.RegisteredClaims{ID: "1"}})
+	if err != nil || string(b) != `{"jti":"1"}` {
+		t.Errorf("json.Marshal() = %s, %v", b, err)
+	}
+}
+
+func TestClaims_ValidTimeErrors(t *testing.T) {
+	now := time.Unix(time.Now().Unix(), 0)
+	exp, nbf := now.Add(-37*time.Second), now.Add(time.Minute)
+
+	expired := []jwt.Claims{
+		&jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(exp)},
+		jwt.MapClaims{"exp": float64(exp.Unix())},
+		&jwt.StandardClaims{ExpiresAt: exp.Unix()},
+	}
+	for _, c := range expired {
+		err := c.Valid()
+		var expErr *jwt.TokenExpiredError
+		if !errors.As(err, &expErr) || !errors.Is(err, jwt.ErrTokenExpired) {
+			t.Fatalf("%T.Valid() error = %v, want %T", c, err, expErr)
+		}
+		if !expErr.ExpiresAt.Equal(exp) || expErr.ExpiredBy < 37*time.Second || expErr.ExpiredBy != expErr.Now.Sub(exp) {
+			t.Errorf("%T.Valid() error = %+v", c, expErr)
+		}
+	}
+
+	notValidYet := []jwt.Claims{
+		&jwt.RegisteredClaims{NotBefore: jwt.NewNumericDate(nbf)},
+		jwt.MapClaims{"nbf": float64(nbf.Unix())},
+		&jwt.StandardClaims{NotBefore: nbf.Unix()},
+	}
+	for _, c := range notValidYet {
+		err := c.Valid()
+		var nbfErr *jwt.TokenNotValidYetError
+		if !errors.As(err, &nbfErr) || !errors.Is(err, jwt.ErrTokenNotValidYet) {
+			t.Fatalf("%T.Valid() error = %v, want %T", c, err, nbfErr)
+		}
+		if !nbfErr.NotBefore.Equal(nbf) || nbfErr.ValidIn > time.Minute || nbfErr.ValidIn != nbf.Sub(nbfErr.Now) {
+			t.Errorf("%T.Valid() error = %+v", c, nbfErr)
+		}
+	}
+}
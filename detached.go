@@ -0,0 +1,152 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/base32"
+	"encoding/base64"
+	"io"
+	"strings"
+)
+
+// VerifyDetached verifies a JWS with a detached payload (RFC 7515, appendix F), i.e. a compact
+// serialization with an empty payload segment such as "header..signature", against the payload
+// read from payload. For the HMAC, RSA, RSA-PSS and ECDSA signing methods, the payload is hashed
+// while it is read, so that large payloads, e.g. multi-GB files, are never held in memory. Other
+// signing methods, such as EdDSA, need the whole signing input and read the payload into memory.
+// Unless the "b64" header is false (RFC 7797), the base64url encoding of the payload is signed.
+//
+// The returned token has its Header, Method and Signature populated, but no Claims, since the
+// payload is not a claims set. Errors reading the payload are returned as is.
+func VerifyDetached(jws string, payload io.Reader, keyFunc Keyfunc, options ...ParserOption) (*Token, error) {
+	return NewParser(options...).VerifyDetached(jws, payload, keyFunc)
+}
+
+// VerifyDetached is like the package level VerifyDetached, using this parser. Options concerning
+// the signing method, the header and the key, such as WithValidMethods, WithKeyIDRequired and
+// WithStrictKeyTypeCheck, are applied; options concerning the claims are not. As for tokens, the
+// "none" signing method is refused unless claims validation is skipped.
+func (p *Parser) VerifyDetached(jws string, payload io.Reader, keyFunc Keyfunc) (*Token, error) {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return nil, NewValidationError("detached JWS must have an empty payload segment", ValidationErrorMalformed)
+	}
+
+	token := &Token{Raw: jws, Signature: parts[2], base32: p.base32}
+
+	// parse Header
+	if err := p.decodeHeader(token, parts[0]); err != nil {
+		return token, err
+	}
+	unencoded, err := unencodedPayload(token.Header)
+	if err != nil {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+
+	// Lookup signature method
+	if err = lookupMethod(token); err != nil {
+		return token, err
+	}
+	if err = p.checkMethod(token); err != nil {
+		return token, err
+	}
+	if err = p.checkHeader(token); err != nil {
+		return token, err
+	}
+
+	// Lookup key
+	key, err := p.resolveKey(keyFunc, token)
+	if err != nil {
+		return token, err
+	}
+
+	sig, err := p.decodeSegment(token.Signature)
+	if err != nil {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+
+	// Hash the signing input while reading the payload. The writers do not fail, so any error is an
+	// error reading the payload.
+	w, verify, err := detachedVerifier(token.Method, key)
+	if err != nil {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorSignatureInvalid}
+	}
+	if err = p.writeSigningInput(w, parts[0], payload, unencoded); err != nil {
+		return token, err
+	}
+	if err = verify(sig); err != nil {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorSignatureInvalid}
+	}
+
+	token.Valid = true
+	return token, nil
+}
+
+// detachedVerifier returns a writer, to which the signing input of a detached JWS is to be
+// written, and a function verifying the signature over the written input. The input is hashed
+// while it is written, if method supports it.
+func detachedVerifier(method SigningMethod, key interface{}) (io.Writer, func(sig []byte) error, error) {
+	switch m := method.(type) {
+	case *SigningMethodHMAC:
+		keyBytes, ok := m.keyBytes(key)
+		if !ok {
+			return nil, nil, ErrInvalidKeyType
+		}
+		if !m.Hash.Available() {
+			return nil, nil, ErrHashUnavailable
+		}
+
+		mac := hmac.New(m.Hash.New, keyBytes)
+		return mac, func(sig []byte) error {
+			if !hmac.Equal(sig, mac.Sum(nil)) {
+				return ErrSignatureInvalid
+			}
+			return nil
+		}, nil
+	case DigestVerifier:
+		if !m.HashFunc().Available() {
+			return nil, nil, ErrHashUnavailable
+		}
+
+		hasher := m.HashFunc().New()
+		return hasher, func(sig []byte) error {
+			return m.VerifyDigest(hasher.Sum(nil), sig, key)
+		}, nil
+	default:
+		buf := new(bytes.Buffer)
+		return buf, func(sig []byte) error {
+			if bv, ok := method.(bytesVerifier); ok {
+				return bv.VerifyBytes(buf.String(), sig, key)
+			}
+			return method.Verify(buf.String(), EncodeSegment(sig), key)
+		}, nil
+	}
+}
+
+// writeSigningInput writes the signing input of a detached JWS to w: the encoded header, a period
+// and the payload, which is base64url encoded unless unencoded is set. If the parser uses
+// WithBase32Encoding, the header and payload are encoded in upper case base32 instead.
+func (p *Parser) writeSigningInput(w io.Writer, header string, payload io.Reader, unencoded bool) error {
+	if p.base32 {
+		header = strings.ToUpper(header)
+	}
+	if _, err := io.WriteString(w, header+"."); err != nil {
+		return err
+	}
+
+	if unencoded {
+		_, err := io.Copy(w, payload)
+		return err
+	}
+
+	var enc io.WriteCloser
+	if p.base32 {
+		enc = base32.NewEncoder(base32Encoding, w)
+	} else {
+		enc = base64.NewEncoder(base64.RawURLEncoding, w)
+	}
+	if _, err := io.Copy(enc, payload); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,169 @@
+package jwt_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// signDetached creates a detached JWS of payload using method.
+func signDetached(t *testing.T, method jwt.SigningMethod, header string, payload []byte, key interface{}) string {
+	signingInput := jwt.EncodeSegment([]byte(header)) + "."
+	if strings.Contains(header, `"b64":false`) {
+		signingInput += string(payload)
+	} else {
+		signingInput += jwt.EncodeSegment(payload)
+	}
+
+	sig, err := method.Sign(signingInput, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return jwt.EncodeSegment([]byte(header)) + ".." + sig
+}
+
+func TestVerifyDetached(t *testing.T) {
+	edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A payload larger than the buffers used for copying
+	payload := bytes.Repeat([]byte("large artifact\n"), 10000)
+
+	tests := []struct {
+		name       string
+		method     jwt.SigningMethod
+		header     string
+		privateKey interface{}
+		publicKey  interface{}
+	}{
+		{"HS256", jwt.SigningMethodHS256, `{"alg":"HS256"}`, hmacTestKey, hmacTestKey},
+		{"RS256", jwt.SigningMethodRS256, `{"alg":"RS256"}`, jwtTestRSAPrivateKey, jwtTestDefaultKey},
+		{"PS256", jwt.SigningMethodPS256, `{"alg":"PS256"}`, jwtTestRSAPrivateKey, jwtTestDefaultKey},
+		{"ES256", jwt.SigningMethodES256, `{"alg":"ES256"}`, jwtTestEC256PrivateKey, jwtTestEC256PublicKey},
+		{"EdDSA", jwt.SigningMethodEdDSA, `{"alg":"EdDSA"}`, edPrivateKey, edPublicKey},
+		{"unencoded payload", jwt.SigningMethodRS256, `{"alg":"RS256","b64":false,"crit":["b64"]}`, jwtTestRSAPrivateKey, jwtTestDefaultKey},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			jws := signDetached(t, tc.method, tc.header, payload, tc.privateKey)
+			keyFunc := func(*jwt.Token) (interface{}, error) { return tc.publicKey, nil }
+
+			token, err := jwt.VerifyDetached(jws, bytes.NewReader(payload), keyFunc)
+			if err != nil {
+				t.Fatalf("VerifyDetached() error = %v", err)
+			}
+			if !token.Valid || token.Method != tc.method || token.Claims != nil {
+				t.Errorf("VerifyDetached() token = %+v, want valid token without claims", token)
+			}
+
+			tampered := append([]byte{}, payload...)
+			tampered[len(tampered)-2] = '!'
+			if _, err = jwt.VerifyDetached(jws, bytes.NewReader(tampered), keyFunc); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+				t.Errorf("VerifyDetached() error = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+			}
+		})
+	}
+}
+
+func TestVerifyDetached_Errors(t *testing.T) {
+	payload := []byte("artifact")
+	jws := signDetached(t, jwt.SigningMethodRS256, `{"alg":"RS256"}`, payload, jwtTestRSAPrivateKey)
+
+	// Errors reading the payload are returned as is
+	if _, err := jwt.VerifyDetached(jws, iotest.TimeoutReader(iotest.OneByteReader(bytes.NewReader(payload))), defaultKeyFunc); err != iotest.ErrTimeout {
+		t.Errorf("VerifyDetached() error = %v, want %v", err, iotest.ErrTimeout)
+	}
+
+	// Tokens with an attached payload are refused
+	parts := strings.Split(jws, ".")
+	attached := parts[0] + "." + jwt.EncodeSegment(payload) + "." + parts[2]
+	if _, err := jwt.VerifyDetached(attached, bytes.NewReader(payload), defaultKeyFunc); !errors.Is(err, jwt.ErrTokenMalformed) {
+		t.Errorf("VerifyDetached() error = %v, want %v", err, jwt.ErrTokenMalformed)
+	}
+
+	// Parser options concerning the signing method are applied
+	if _, err := jwt.VerifyDetached(jws, bytes.NewReader(payload), defaultKeyFunc, jwt.WithValidMethods([]string{"ES256"})); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Errorf("VerifyDetached() error = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+	}
+
+	// Parser options concerning the header are applied before the key is looked up
+	headerTests := []struct {
+		name    string
+		header  string
+		options []jwt.ParserOption
+		err     error
+	}{
+		{"embedded key URL", `{"alg":"RS256","kid":"1","jku":"https://example.com/keys"}`, []jwt.ParserOption{jwt.WithRejectEmbeddedKeyURLs()}, jwt.ErrTokenEmbeddedKeyURL},
+		{"key ID missing", `{"alg":"RS256"}`, []jwt.ParserOption{jwt.WithKeyIDRequired()}, jwt.ErrTokenKeyIDMissing},
+		{"unexpected type", `{"alg":"RS256","typ":"JWT"}`, []jwt.ParserOption{jwt.WithExpectedType("at+jwt")}, jwt.ErrTokenInvalidType},
+	}
+	for _, tc := range headerTests {
+		t.Run(tc.name, func(t *testing.T) {
+			jws := signDetached(t, jwt.SigningMethodRS256, tc.header, payload, jwtTestRSAPrivateKey)
+			keyFunc := func(*jwt.Token) (interface{}, error) {
+				t.Error("Keyfunc called for refused header")
+				return jwtTestDefaultKey, nil
+			}
+
+			if _, err := jwt.VerifyDetached(jws, bytes.NewReader(payload), keyFunc, tc.options...); !errors.Is(err, tc.err) {
+				t.Errorf("VerifyDetached() error = %v, want %v", err, tc.err)
+			}
+		})
+	}
+
+	// The key is checked as for tokens
+	keyTests := []struct {
+		name    string
+		jws     string
+		key     interface{}
+		options []jwt.ParserOption
+		err     error
+	}{
+		{"key of other method", jws, hmacTestKey, nil, jwt.ErrInvalidKeyType},
+		{"key of other method strict", jws, hmacTestKey, []jwt.ParserOption{jwt.WithStrictKeyTypeCheck()}, jwt.ErrInvalidKeyType},
+		{"empty hmac secret", signDetached(t, jwt.SigningMethodHS256, `{"alg":"HS256"}`, payload, hmacTestKey), []byte{}, nil, jwt.ErrKeyfuncNoKey},
+		{"none with claims validation", signDetached(t, jwt.SigningMethodNone, `{"alg":"none"}`, payload, jwt.UnsafeAllowNoneSignatureType), jwt.UnsafeAllowNoneSignatureType, nil, jwt.ErrNoneClaimsValidation},
+	}
+	for _, tc := range keyTests {
+		t.Run(tc.name, func(t *testing.T) {
+			keyFunc := func(*jwt.Token) (interface{}, error) { return tc.key, nil }
+			if _, err := jwt.VerifyDetached(tc.jws, bytes.NewReader(payload), keyFunc, tc.options...); !errors.Is(err, tc.err) {
+				t.Errorf("VerifyDetached() error = %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestVerifyDetached_Base32(t *testing.T) {
+	payload := []byte(`{"foo":"bar"}`)
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"}, jwt.WithBase32Segments()).SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(tokenString, ".")
+	keyFunc := func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	for _, jws := range []string{parts[0] + ".." + parts[2], strings.ToLower(parts[0] + ".." + parts[2])} {
+		token, err := jwt.VerifyDetached(jws, bytes.NewReader(payload), keyFunc, jwt.WithBase32Encoding())
+		if err != nil {
+			t.Fatalf("VerifyDetached() error = %v", err)
+		}
+		if !token.Valid || token.Method != jwt.SigningMethodHS256 {
+			t.Errorf("VerifyDetached() token = %+v, want valid HS256 token", token)
+		}
+	}
+
+	if _, err = jwt.VerifyDetached(parts[0]+".."+parts[2], bytes.NewReader([]byte(`{"foo":"baz"}`)), keyFunc, jwt.WithBase32Encoding()); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Errorf("VerifyDetached() error = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+	}
+}
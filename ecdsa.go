@@ -3,7 +3,9 @@ package jwt
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
+	"encoding/asn1"
 	"errors"
 	"math/big"
 )
@@ -47,6 +49,11 @@ func init() {
 	RegisterSigningMethod(SigningMethodES512.Alg(), func() SigningMethod {
 		return SigningMethodES512
 	})
+
+	// Deterministic variants, see SigningMethodECDSADeterministic
+	SigningMethodES256Deterministic = &SigningMethodECDSADeterministic{SigningMethodES256}
+	SigningMethodES384Deterministic = &SigningMethodECDSADeterministic{SigningMethodES384}
+	SigningMethodES512Deterministic = &SigningMethodECDSADeterministic{SigningMethodES512}
 }
 
 func (m *SigningMethodECDSA) Alg() string {
@@ -56,14 +63,30 @@ func (m *SigningMethodECDSA) Alg() string {
 // Verify implements token verification for the SigningMethod.
 // For this verify method, key must be an ecdsa.PublicKey struct
 func (m *SigningMethodECDSA) Verify(signingString, signature string, key interface{}) error {
-	var err error
-
 	// Decode the signature
-	var sig []byte
-	if sig, err = DecodeSegment(signature); err != nil {
+	sig, err := DecodeSegment(signature)
+	if err != nil {
 		return err
 	}
 
+	return m.VerifyBytes(signingString, sig, key)
+}
+
+// VerifyBytes works like Verify, but takes the already decoded signature.
+func (m *SigningMethodECDSA) VerifyBytes(signingString string, sig []byte, key interface{}) error {
+	// Create hasher
+	if !m.Hash.Available() {
+		return ErrHashUnavailable
+	}
+	hasher := m.Hash.New()
+	hasher.Write([]byte(signingString))
+
+	return m.VerifyDigest(hasher.Sum(nil), sig, key)
+}
+
+// VerifyDigest implements verification of a signature of a digest computed by the caller, see
+// DigestVerifier. For this verify method, key must be an ecdsa.PublicKey struct
+func (m *SigningMethodECDSA) VerifyDigest(digest, sig []byte, key interface{}) error {
 	// Get the key
 	var ecdsaKey *ecdsa.PublicKey
 	switch k := key.(type) {
@@ -73,6 +96,11 @@ func (m *SigningMethodECDSA) Verify(signingString, signature string, key interfa
 		return ErrInvalidKeyType
 	}
 
+	// The curve of the key must match the algorithm, e.g. P-521 for ES512
+	if ecdsaKey.Curve == nil || ecdsaKey.Curve.Params().BitSize != m.CurveBits {
+		return ErrInvalidKeyType
+	}
+
 	if len(sig) != 2*m.KeySize {
 		return ErrECDSAVerification
 	}
@@ -80,15 +108,12 @@ func (m *SigningMethodECDSA) Verify(signingString, signature string, key interfa
 	r := big.NewInt(0).SetBytes(sig[:m.KeySize])
 	s := big.NewInt(0).SetBytes(sig[m.KeySize:])
 
-	// Create hasher
-	if !m.Hash.Available() {
-		return ErrHashUnavailable
+	if err := checkDigest(m.Hash, digest); err != nil {
+		return err
 	}
-	hasher := m.Hash.New()
-	hasher.Write([]byte(signingString))
 
 	// Verify the signature
-	if verifystatus := ecdsa.Verify(ecdsaKey, hasher.Sum(nil), r, s); verifystatus {
+	if verifystatus := ecdsa.Verify(ecdsaKey, digest, r, s); verifystatus {
 		return nil
 	}
 
@@ -96,47 +121,106 @@ func (m *SigningMethodECDSA) Verify(signingString, signature string, key interfa
 }
 
 // Sign implements token signing for the SigningMethod.
-// For this signing method, key must be an ecdsa.PrivateKey struct
+// For this signing method, key must be an ecdsa.PrivateKey struct or a crypto.Signer
+// with an *ecdsa.PublicKey, e.g. a key stored in an HSM.
 func (m *SigningMethodECDSA) Sign(signingString string, key interface{}) (string, error) {
+	// Create the hasher
+	if !m.Hash.Available() {
+		return "", ErrHashUnavailable
+	}
+
+	hasher := m.Hash.New()
+	hasher.Write([]byte(signingString))
+
+	return m.SignDigest(hasher.Sum(nil), key)
+}
+
+// HashFunc returns the hash function used to compute the digest of the signing string.
+func (m *SigningMethodECDSA) HashFunc() crypto.Hash {
+	return m.Hash
+}
+
+// SignDigest implements signing of a digest computed by the caller, see DigestSigner.
+// For this signing method, key must be an ecdsa.PrivateKey struct or a crypto.Signer
+// with an *ecdsa.PublicKey.
+func (m *SigningMethodECDSA) SignDigest(digest []byte, key interface{}) (string, error) {
 	// Get the key
 	var ecdsaKey *ecdsa.PrivateKey
+	var signer crypto.Signer
+	var curve elliptic.Curve
 	switch k := key.(type) {
 	case *ecdsa.PrivateKey:
 		ecdsaKey = k
+		curve = k.Curve
+	case crypto.Signer:
+		pub, ok := k.Public().(*ecdsa.PublicKey)
+		if !ok {
+			return "", ErrInvalidKeyType
+		}
+		signer = k
+		curve = pub.Curve
 	default:
 		return "", ErrInvalidKeyType
 	}
 
-	// Create the hasher
-	if !m.Hash.Available() {
-		return "", ErrHashUnavailable
+	if err := checkDigest(m.Hash, digest); err != nil {
+		return "", err
 	}
 
-	hasher := m.Hash.New()
-	hasher.Write([]byte(signingString))
+	// Sign the digest and return r, s
+	var r, s *big.Int
+	var err error
+	if ecdsaKey != nil {
+		r, s, err = ecdsa.Sign(rand.Reader, ecdsaKey, digest)
+	} else {
+		r, s, err = signWithSigner(signer, digest, m.Hash)
+	}
+	if err != nil {
+		return "", err
+	}
 
-	// Sign the string and return r, s
-	if r, s, err := ecdsa.Sign(rand.Reader, ecdsaKey, hasher.Sum(nil)); err == nil {
-		curveBits := ecdsaKey.Curve.Params().BitSize
+	return m.encodeSignature(curve, r, s)
+}
 
-		if m.CurveBits != curveBits {
-			return "", ErrInvalidKey
-		}
+// encodeSignature encodes r and s of a signature created using a key on curve.
+func (m *SigningMethodECDSA) encodeSignature(curve elliptic.Curve, r, s *big.Int) (string, error) {
+	curveBits := curve.Params().BitSize
 
-		keyBytes := curveBits / 8
-		if curveBits%8 > 0 {
-			keyBytes += 1
-		}
+	if m.CurveBits != curveBits {
+		return "", ErrInvalidKey
+	}
 
-		// We serialize the outputs (r and s) into big-endian byte arrays
-		// padded with zeros on the left to make sure the sizes work out.
-		// Output must be 2*keyBytes long.
-		out := make([]byte, 2*keyBytes)
-		r.FillBytes(out[0:keyBytes]) // r is assigned to the first half of output.
-		s.FillBytes(out[keyBytes:])  // s is assigned to the second half of output.
+	keyBytes := curveBits / 8
+	if curveBits%8 > 0 {
+		keyBytes += 1
+	}
 
-		return EncodeSegment(out), nil
-	} else {
-		return "", err
+	// We serialize the outputs (r and s) into big-endian byte arrays
+	// padded with zeros on the left to make sure the sizes work out.
+	// Output must be 2*keyBytes long.
+	out := make([]byte, 2*keyBytes)
+	r.FillBytes(out[0:keyBytes]) // r is assigned to the first half of output.
+	s.FillBytes(out[keyBytes:])  // s is assigned to the second half of output.
+
+	return EncodeSegment(out), nil
+}
+
+// signWithSigner signs digest using signer and returns r and s of the signature, which
+// crypto.Signer implementations for ECDSA return in ASN.1 DER encoding.
+func signWithSigner(signer crypto.Signer, digest []byte, hash crypto.Hash) (r, s *big.Int, err error) {
+	der, err := signer.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return nil, nil, err
 	}
+
+	var sig struct {
+		R, S *big.Int
+	}
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, nil, err
+	} else if len(rest) != 0 {
+		return nil, nil, asn1.SyntaxError{Msg: "trailing data"}
+	}
+
+	return sig.R, sig.S, nil
 }
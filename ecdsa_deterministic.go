@@ -0,0 +1,135 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"math/big"
+)
+
+// SigningMethodECDSADeterministic implements the ECDSA family of signing methods with deterministic
+// signatures as specified in RFC 6979, i.e. signing the same input with the same key always yields
+// the same signature, e.g. for golden tests. The signatures are regular ECDSA signatures, so tokens
+// are verified using the corresponding SigningMethodECDSA, which is also used for parsing.
+// Expects *ecdsa.PrivateKey for signing and *ecdsa.PublicKey for verification
+type SigningMethodECDSADeterministic struct {
+	*SigningMethodECDSA
+}
+
+// Specific instances for deterministic EC256 and company. They are not registered, since they share
+// their "alg" with SigningMethodES256 and company.
+var (
+	SigningMethodES256Deterministic *SigningMethodECDSADeterministic
+	SigningMethodES384Deterministic *SigningMethodECDSADeterministic
+	SigningMethodES512Deterministic *SigningMethodECDSADeterministic
+)
+
+// Sign implements token signing for the SigningMethod.
+// For this signing method, key must be an ecdsa.PrivateKey struct
+func (m *SigningMethodECDSADeterministic) Sign(signingString string, key interface{}) (string, error) {
+	if !m.Hash.Available() {
+		return "", ErrHashUnavailable
+	}
+
+	hasher := m.Hash.New()
+	hasher.Write([]byte(signingString))
+
+	return m.SignDigest(hasher.Sum(nil), key)
+}
+
+// SignDigest implements deterministic signing of a digest computed by the caller, see
+// DigestSigner. For this signing method, key must be an ecdsa.PrivateKey struct
+func (m *SigningMethodECDSADeterministic) SignDigest(digest []byte, key interface{}) (string, error) {
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return "", ErrInvalidKeyType
+	}
+
+	if err := checkDigest(m.Hash, digest); err != nil {
+		return "", err
+	}
+
+	curve := ecdsaKey.Curve
+	n := curve.Params().N
+	e := bitsToInt(digest, n)
+
+	// Compute r and s as in ECDSA, but with the nonce k derived from the key and digest
+	var r, s *big.Int
+	nextK := m.nonces(ecdsaKey.D, digest, n)
+	for {
+		k := nextK()
+
+		x, _ := curve.ScalarBaseMult(k.Bytes())
+		r = x.Mod(x, n)
+		if r.Sign() == 0 {
+			continue
+		}
+
+		s = new(big.Int).Mul(r, ecdsaKey.D)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(k, n))
+		s.Mod(s, n)
+		if s.Sign() != 0 {
+			break
+		}
+	}
+
+	return m.encodeSignature(curve, r, s)
+}
+
+// nonces returns a generator of the candidate nonces k for signing digest using the private key d,
+// as specified in RFC 6979, section 3.2.
+func (m *SigningMethodECDSADeterministic) nonces(d *big.Int, digest []byte, n *big.Int) func() *big.Int {
+	size := (n.BitLen() + 7) / 8
+	mac := func(key []byte, data ...[]byte) []byte {
+		h := hmac.New(m.Hash.New, key)
+		for _, b := range data {
+			h.Write(b)
+		}
+		return h.Sum(nil)
+	}
+
+	x := d.FillBytes(make([]byte, size))
+	h := new(big.Int).Mod(bitsToInt(digest, n), n).FillBytes(make([]byte, size))
+
+	v := make([]byte, m.Hash.Size())
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, m.Hash.Size())
+
+	k = mac(k, v, []byte{0x00}, x, h)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x, h)
+	v = mac(k, v)
+
+	first := true
+	return func() *big.Int {
+		for {
+			if !first {
+				k = mac(k, v, []byte{0x00})
+				v = mac(k, v)
+			}
+			first = false
+
+			var t []byte
+			for len(t)*8 < n.BitLen() {
+				v = mac(k, v)
+				t = append(t, v...)
+			}
+
+			if nonce := bitsToInt(t, n); nonce.Sign() > 0 && nonce.Cmp(n) < 0 {
+				return nonce
+			}
+		}
+	}
+}
+
+// bitsToInt converts b to an integer, keeping only its leftmost bits up to the bit length of n,
+// as specified in RFC 6979, section 2.3.2.
+func bitsToInt(b []byte, n *big.Int) *big.Int {
+	i := new(big.Int).SetBytes(b)
+	if excess := len(b)*8 - n.BitLen(); excess > 0 {
+		i.Rsh(i, uint(excess))
+	}
+	return i
+}
@@ -0,0 +1,87 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestECDSADeterministicSign(t *testing.T) {
+	hexInt := func(s string) *big.Int {
+		i, _ := new(big.Int).SetString(s, 16)
+		return i
+	}
+
+	// Test vectors for P-256 and SHA-256 from RFC 6979, appendix A.2.5
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     hexInt("60FED4BA255A9D31C961EB74C6356D68C049B8923B61FA6CE669622E60F29FB6"),
+			Y:     hexInt("7903FE1008B8BC99A41AE9E95628BC64F2F1B20C2D7E9F5177A3C294D4462299"),
+		},
+		D: hexInt("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721"),
+	}
+
+	tests := []struct {
+		message string
+		r, s    string
+	}{
+		{"sample", "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716", "F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"},
+		{"test", "F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367", "019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083"},
+	}
+
+	for _, tc := range tests {
+		sig, err := jwt.SigningMethodES256Deterministic.Sign(tc.message, key)
+		if err != nil {
+			t.Fatalf("[%v] Sign() error = %v", tc.message, err)
+		}
+
+		raw, _ := jwt.DecodeSegment(sig)
+		want := make([]byte, 64)
+		hexInt(tc.r).FillBytes(want[:32])
+		hexInt(tc.s).FillBytes(want[32:])
+		if string(raw) != string(want) {
+			t.Errorf("[%v] Sign() = %x, want %x", tc.message, raw, want)
+		}
+
+		if err = jwt.SigningMethodES256.Verify(tc.message, sig, &key.PublicKey); err != nil {
+			t.Errorf("[%v] Verify() error = %v", tc.message, err)
+		}
+	}
+}
+
+func TestECDSADeterministicSign_Tokens(t *testing.T) {
+	for _, method := range []*jwt.SigningMethodECDSADeterministic{
+		jwt.SigningMethodES256Deterministic,
+		jwt.SigningMethodES384Deterministic,
+		jwt.SigningMethodES512Deterministic,
+	} {
+		curve := map[string]elliptic.Curve{"ES256": elliptic.P256(), "ES384": elliptic.P384(), "ES512": elliptic.P521()}[method.Alg()]
+		key, err := ecdsa.GenerateKey(curve, strings.NewReader(strings.Repeat("seed", 100)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		first, err := jwt.NewWithClaims(method, jwt.MapClaims{"foo": "bar"}).SignedString(key)
+		if err != nil {
+			t.Fatalf("[%v] SignedString() error = %v", method.Alg(), err)
+		}
+		second, _ := jwt.NewWithClaims(method, jwt.MapClaims{"foo": "bar"}).SignedString(key)
+		if first != second {
+			t.Errorf("[%v] SignedString() is not deterministic: %v != %v", method.Alg(), first, second)
+		}
+
+		token, err := jwt.Parse(first, func(t *jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+		if err != nil || token.Method != method.SigningMethodECDSA {
+			t.Errorf("[%v] Parse() error = %v, method = %v", method.Alg(), err, token.Method)
+		}
+
+		if _, err = method.Sign("foo", jwtTestRSAPrivateKey); err != jwt.ErrInvalidKeyType {
+			t.Errorf("[%v] Sign() error = %v, want %v", method.Alg(), err, jwt.ErrInvalidKeyType)
+		}
+	}
+}
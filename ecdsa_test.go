@@ -2,7 +2,10 @@ package jwt_test
 
 import (
 	"crypto/ecdsa"
+	"encoding/base64"
+	"errors"
 	"io/ioutil"
+	"math/big"
 	"strings"
 	"testing"
 
@@ -75,6 +78,71 @@ func TestECDSAVerify(t *testing.T) {
 	}
 }
 
+func TestECDSAVerifyCurveMismatch(t *testing.T) {
+	keys := map[string]string{
+		"ES256": "test/ec256-public.pem",
+		"ES384": "test/ec384-public.pem",
+		"ES512": "test/ec512-public.pem",
+	}
+
+	for _, data := range ecdsaTestData {
+		if !data.valid {
+			continue
+		}
+
+		parts := strings.Split(data.tokenString, ".")
+		method := jwt.GetSigningMethod(data.alg)
+
+		for alg, file := range keys {
+			if alg == data.alg {
+				continue
+			}
+
+			key, _ := ioutil.ReadFile(file)
+			ecdsaKey, err := jwt.ParseECPublicKeyFromPEM(key)
+			if err != nil {
+				t.Fatalf("Unable to parse ECDSA public key: %v", err)
+			}
+
+			err = method.Verify(strings.Join(parts[0:2], "."), parts[2], ecdsaKey)
+			if !errors.Is(err, jwt.ErrInvalidKeyType) {
+				t.Errorf("[%v] Verify with %v key error = %v, want %v", data.name, alg, err, jwt.ErrInvalidKeyType)
+			}
+		}
+	}
+}
+
+func TestECDSASignWithSigner(t *testing.T) {
+	for _, data := range ecdsaTestData {
+		if !data.valid {
+			continue
+		}
+
+		key, _ := ioutil.ReadFile(data.keys["private"])
+		ecdsaKey, err := jwt.ParseECPrivateKeyFromPEM(key)
+		if err != nil {
+			t.Fatalf("Unable to parse ECDSA private key: %v", err)
+		}
+
+		parts := strings.Split(data.tokenString, ".")
+		toSign := strings.Join(parts[0:2], ".")
+		method := jwt.GetSigningMethod(data.alg)
+
+		sig, err := method.Sign(toSign, opaqueSigner{ecdsaKey})
+		if err != nil {
+			t.Fatalf("[%v] Error signing with crypto.Signer: %v", data.name, err)
+		}
+		if err = method.Verify(toSign, sig, &ecdsaKey.PublicKey); err != nil {
+			t.Errorf("[%v] Signature of crypto.Signer is invalid: %v", data.name, err)
+		}
+	}
+
+	// The signer must hold an ECDSA key
+	if _, err := jwt.SigningMethodES256.Sign("foo", opaqueSigner{jwtTestRSAPrivateKey}); !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("Signing with a non-ECDSA crypto.Signer error = %v, want %v", err, jwt.ErrInvalidKeyType)
+	}
+}
+
 func TestECDSASign(t *testing.T) {
 	for _, data := range ecdsaTestData {
 		var err error
@@ -162,3 +230,39 @@ func BenchmarkECDSASigning(b *testing.B) {
 		})
 	}
 }
+
+func TestParseECPublicKeyFromJWK(t *testing.T) {
+	pub := jwtTestEC256PublicKey.(*ecdsa.PublicKey)
+	encode := func(i *big.Int) string {
+		b := make([]byte, 32)
+		return base64.RawURLEncoding.EncodeToString(i.FillBytes(b))
+	}
+	x, y := encode(pub.X), encode(pub.Y)
+
+	key, err := jwt.ParseECPublicKeyFromJWK("P-256", x, y)
+	if err != nil {
+		t.Fatalf("ParseECPublicKeyFromJWK() error = %v", err)
+	}
+	if !key.Equal(pub) {
+		t.Fatalf("ParseECPublicKeyFromJWK() = %v, want %v", key, pub)
+	}
+	if _, err = jwt.Parse(signToken(jwt.MapClaims{"foo": "bar"}, jwt.SigningMethodES256), func(t *jwt.Token) (interface{}, error) { return key, nil }); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		crv, x, y string
+	}{
+		{"unknown curve", "secp256k1", x, y},
+		{"wrong curve", "P-384", x, y},
+		{"padded coordinate", "P-256", x + "=", y},
+		{"short coordinate", "P-256", x[:len(x)-2], y},
+		{"point not on curve", "P-256", y, x},
+	}
+	for _, tc := range tests {
+		if _, err = jwt.ParseECPublicKeyFromJWK(tc.crv, tc.x, tc.y); err == nil {
+			t.Errorf("[%v] ParseECPublicKeyFromJWK() expected error", tc.name)
+		}
+	}
+}
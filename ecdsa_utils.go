@@ -2,9 +2,12 @@ package jwt
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 	"errors"
+	"math/big"
 )
 
 var (
@@ -67,3 +70,46 @@ func ParseECPublicKeyFromPEM(key []byte) (*ecdsa.PublicKey, error) {
 
 	return pkey, nil
 }
+
+// ParseECPublicKeyFromJWK creates an ECDSA public key from the "crv", "x" and "y" parameters of a
+// JSON Web Key (RFC 7518, section 6.2.1). The curve must be one of P-256, P-384 or P-521 and the
+// coordinates must be base64url encoded without padding and denote a point on the curve
+func ParseECPublicKeyFromJWK(crv, x, y string) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, ErrNotECPublicKey
+	}
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, err
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(y)
+	if err != nil {
+		return nil, err
+	}
+
+	// The coordinates must have the full length of the curve's field elements
+	size := (curve.Params().BitSize + 7) / 8
+	if len(xBytes) != size || len(yBytes) != size {
+		return nil, ErrNotECPublicKey
+	}
+
+	pkey := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}
+	if !curve.IsOnCurve(pkey.X, pkey.Y) {
+		return nil, ErrNotECPublicKey
+	}
+
+	return pkey, nil
+}
@@ -35,7 +35,17 @@ func (m *SigningMethodEd25519) Alg() string {
 // Verify implements token verification for the SigningMethod.
 // For this verify method, key must be an ed25519.PublicKey
 func (m *SigningMethodEd25519) Verify(signingString, signature string, key interface{}) error {
-	var err error
+	// Decode the signature
+	sig, err := DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	return m.VerifyBytes(signingString, sig, key)
+}
+
+// VerifyBytes works like Verify, but takes the already decoded signature.
+func (m *SigningMethodEd25519) VerifyBytes(signingString string, sig []byte, key interface{}) error {
 	var ed25519Key ed25519.PublicKey
 	var ok bool
 
@@ -47,12 +57,6 @@ func (m *SigningMethodEd25519) Verify(signingString, signature string, key inter
 		return ErrInvalidKey
 	}
 
-	// Decode the signature
-	var sig []byte
-	if sig, err = DecodeSegment(signature); err != nil {
-		return err
-	}
-
 	// Verify the signature
 	if !ed25519.Verify(ed25519Key, []byte(signingString), sig) {
 		return ErrEd25519Verification
@@ -1,6 +1,7 @@
 package jwt_test
 
 import (
+	"crypto/ed25519"
 	"io/ioutil"
 	"strings"
 	"testing"
@@ -82,3 +83,33 @@ func TestEd25519Sign(t *testing.T) {
 		}
 	}
 }
+
+func TestParseEdPrivateKeyFromSeed(t *testing.T) {
+	pemKey, _ := ioutil.ReadFile("test/ed25519-private.pem")
+	want, err := jwt.ParseEdPrivateKeyFromPEM(pemKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := jwt.ParseEdPrivateKeyFromSeed(want.(ed25519.PrivateKey).Seed())
+	if err != nil {
+		t.Fatalf("ParseEdPrivateKeyFromSeed() error = %v", err)
+	}
+	if !key.Equal(want) {
+		t.Errorf("ParseEdPrivateKeyFromSeed() = %x, want %x", key, want)
+	}
+
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"foo": "bar"}).SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	if _, err = jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) { return key.Public(), nil }); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+
+	for _, seed := range [][]byte{nil, make([]byte, ed25519.SeedSize-1), make([]byte, ed25519.PrivateKeySize)} {
+		if _, err = jwt.ParseEdPrivateKeyFromSeed(seed); err != jwt.ErrNotEdPrivateKey {
+			t.Errorf("ParseEdPrivateKeyFromSeed(%d bytes) error = %v, want %v", len(seed), err, jwt.ErrNotEdPrivateKey)
+		}
+	}
+}
@@ -38,6 +38,16 @@ func ParseEdPrivateKeyFromPEM(key []byte) (crypto.PrivateKey, error) {
 	return pkey, nil
 }
 
+// ParseEdPrivateKeyFromSeed creates an Edwards curve private key from a raw 32 byte seed,
+// as described in RFC 8032
+func ParseEdPrivateKeyFromSeed(seed []byte) (ed25519.PrivateKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, ErrNotEdPrivateKey
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
 // ParseEdPublicKeyFromPEM parses a PEM-encoded Edwards curve public key
 func ParseEdPublicKeyFromPEM(key []byte) (crypto.PublicKey, error) {
 	var err error
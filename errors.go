@@ -2,6 +2,8 @@ package jwt
 
 import (
 	"errors"
+	"fmt"
+	"time"
 )
 
 // Error constants
@@ -9,6 +11,16 @@ var (
 	ErrInvalidKey      = errors.New("key is invalid")
 	ErrInvalidKeyType  = errors.New("key is of invalid type")
 	ErrHashUnavailable = errors.New("the requested hash function is unavailable")
+	ErrInvalidType     = errors.New("claim is of invalid type")
+	ErrKeyfuncNoKey    = errors.New("keyfunc returned no key")
+	ErrKeyfuncPanic    = errors.New("keyfunc panicked")
+	ErrInvalidDigest   = errors.New("digest has invalid size for the hash function")
+	ErrInvalidLifetime = errors.New("token lifetime must be positive")
+
+	ErrInvalidB64Header       = errors.New("b64 header must be a boolean and listed in crit")
+	ErrUnencodedPayloadPeriod = errors.New("unencoded payload must not contain '.'")
+	ErrTokenAlgMismatch       = errors.New("token header alg does not match the signing method")
+	ErrNoneClaimsValidation   = errors.New("'none' signature type requires claims validation to be disabled")
 
 	ErrTokenMalformed        = errors.New("token is malformed")
 	ErrTokenUnverifiable     = errors.New("token is unverifiable")
@@ -21,6 +33,20 @@ var (
 	ErrTokenNotValidYet      = errors.New("token is not valid yet")
 	ErrTokenInvalidId        = errors.New("token has invalid id")
 	ErrTokenInvalidClaims    = errors.New("token has invalid claims")
+
+	ErrTokenRequiredClaimMissing = errors.New("token is missing required claim")
+	ErrTokenTooOld               = errors.New("token is too old")
+	ErrTokenExpiryTooFar         = errors.New("token expires too far in the future")
+	ErrTokenKeyIDMissing         = errors.New("token header is missing kid")
+	ErrTokenTooManyClaims        = errors.New("token has too many claims")
+	ErrTokenDuplicateClaim       = errors.New("token has duplicate claim")
+	ErrTokenPayloadNotJSON       = errors.New("token payload is not valid JSON")
+	ErrTokenEmbeddedKeyURL       = errors.New("token header contains a key URL")
+	ErrTokenInvalidType          = errors.New("token has invalid type")
+	ErrTokenInvalidNonce         = errors.New("token has invalid nonce")
+	ErrTokenInvalidClientID      = errors.New("token has invalid client_id")
+	ErrTokenClaimsNotCanonical   = errors.New("token claims do not match their decoded form")
+	ErrTokenIssuedBeforeCutoff   = errors.New("token was issued before the cutoff")
 )
 
 // The errors that might occur when parsing and validating a token
@@ -47,6 +73,64 @@ func NewValidationError(errorText string, errorFlags uint32) *ValidationError {
 	}
 }
 
+// TokenExpiredError is the error of a token whose "exp" claim has passed. It wraps ErrTokenExpired
+// and is set as Inner of the ValidationError returned by the Valid methods of the claims types in
+// this package.
+type TokenExpiredError struct {
+	ExpiresAt time.Time     // The time of the "exp" claim
+	Now       time.Time     // The time at which the token was validated
+	ExpiredBy time.Duration // How long ago the token expired
+}
+
+func (e *TokenExpiredError) Error() string {
+	return fmt.Sprintf("%s by %s", ErrTokenExpired, e.ExpiredBy)
+}
+
+// Unwrap returns ErrTokenExpired.
+func (e *TokenExpiredError) Unwrap() error {
+	return ErrTokenExpired
+}
+
+// KeyfuncPanicError is the error of a Keyfunc that panicked, if the parser was created using
+// WithRecoverKeyfunc. It matches ErrKeyfuncPanic and, if the panic value is an error, wraps it.
+type KeyfuncPanicError struct {
+	Value interface{} // The value passed to panic
+	Stack []byte      // The stack trace of the panicking goroutine
+}
+
+func (e *KeyfuncPanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrKeyfuncPanic, e.Value)
+}
+
+// Is reports whether target is ErrKeyfuncPanic.
+func (e *KeyfuncPanicError) Is(target error) bool {
+	return target == ErrKeyfuncPanic
+}
+
+// Unwrap returns the panic value, if it is an error.
+func (e *KeyfuncPanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// TokenNotValidYetError is the error of a token whose "nbf" claim has not been reached yet. It
+// wraps ErrTokenNotValidYet and is set as Inner of the ValidationError returned by the Valid
+// methods of the claims types in this package.
+type TokenNotValidYetError struct {
+	NotBefore time.Time     // The time of the "nbf" claim
+	Now       time.Time     // The time at which the token was validated
+	ValidIn   time.Duration // How long until the token becomes valid
+}
+
+func (e *TokenNotValidYetError) Error() string {
+	return fmt.Sprintf("%s for another %s", ErrTokenNotValidYet, e.ValidIn)
+}
+
+// Unwrap returns ErrTokenNotValidYet.
+func (e *TokenNotValidYetError) Unwrap() error {
+	return ErrTokenNotValidYet
+}
+
 // ValidationError represents an error from Parse if token is not valid
 type ValidationError struct {
 	Inner  error  // stores the error returned by external dependencies, i.e.: KeyFunc
@@ -110,3 +194,46 @@ func (e *ValidationError) Is(err error) bool {
 
 	return false
 }
+
+// validationErrorFlags maps the flags of a ValidationError to the corresponding errors.
+var validationErrorFlags = []struct {
+	flag uint32
+	err  error
+}{
+	{ValidationErrorMalformed, ErrTokenMalformed},
+	{ValidationErrorUnverifiable, ErrTokenUnverifiable},
+	{ValidationErrorSignatureInvalid, ErrTokenSignatureInvalid},
+	{ValidationErrorAudience, ErrTokenInvalidAudience},
+	{ValidationErrorExpired, ErrTokenExpired},
+	{ValidationErrorIssuedAt, ErrTokenUsedBeforeIssued},
+	{ValidationErrorIssuer, ErrTokenInvalidIssuer},
+	{ValidationErrorNotValidYet, ErrTokenNotValidYet},
+	{ValidationErrorId, ErrTokenInvalidId},
+	{ValidationErrorClaimsInvalid, ErrTokenInvalidClaims},
+}
+
+// AsValidationErrors returns the individual failures recorded in a ValidationError contained in
+// err, e.g. to map each of them to a user-facing reason. The inner error, which is the most
+// specific one, comes first, e.g. a *TokenExpiredError, followed by the errors corresponding to
+// the remaining error flags, such as ErrTokenNotValidYet, in the order of the flags. Since a
+// ValidationError only retains the inner error of the last failure, earlier failures are only
+// reported by their generic error. AsValidationErrors returns nil, if err does not contain a
+// ValidationError or the ValidationError has no error flags set.
+func AsValidationErrors(err error) []error {
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.valid() {
+		return nil
+	}
+
+	var errs []error
+	if vErr.Inner != nil {
+		errs = append(errs, vErr.Inner)
+	}
+	for _, f := range validationErrorFlags {
+		if vErr.Errors&f.flag != 0 && (vErr.Inner == nil || !errors.Is(vErr.Inner, f.err)) {
+			errs = append(errs, f.err)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,50 @@
+package jwt_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestAsValidationErrors(t *testing.T) {
+	now := time.Now()
+
+	// Expired and not valid yet, with the inner error of the last failure
+	claims := &jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(-time.Hour)),
+		NotBefore: jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+	errs := jwt.AsValidationErrors(claims.Valid())
+	if len(errs) != 2 {
+		t.Fatalf("AsValidationErrors() = %v, want 2 errors", errs)
+	}
+	var notValidYet *jwt.TokenNotValidYetError
+	if !errors.As(errs[0], &notValidYet) {
+		t.Errorf("AsValidationErrors()[0] = %v, want %T", errs[0], notValidYet)
+	}
+	if errs[1] != jwt.ErrTokenExpired {
+		t.Errorf("AsValidationErrors()[1] = %v, want %v", errs[1], jwt.ErrTokenExpired)
+	}
+
+	// Failed claim checks of the parser, wrapped by the caller
+	err := fmt.Errorf("authentication failed: %w", jwt.ValidateClaims(jwt.MapClaims{"iss": "foo"}, jwt.WithIssuer("bar"), jwt.WithRequireJTI()))
+	errs = jwt.AsValidationErrors(err)
+	if len(errs) != 3 || !errors.Is(errs[0], jwt.ErrTokenRequiredClaimMissing) || errs[1] != jwt.ErrTokenInvalidIssuer || errs[2] != jwt.ErrTokenInvalidId {
+		t.Errorf("AsValidationErrors() = %v, want [missing jti, invalid issuer, invalid id]", errs)
+	}
+
+	// Errors without inner error
+	errs = jwt.AsValidationErrors(jwt.NewValidationError("token is bad", jwt.ValidationErrorMalformed))
+	if len(errs) != 1 || errs[0] != jwt.ErrTokenMalformed {
+		t.Errorf("AsValidationErrors() = %v, want [%v]", errs, jwt.ErrTokenMalformed)
+	}
+
+	for _, err := range []error{nil, errors.New("foo"), &jwt.ValidationError{}} {
+		if errs := jwt.AsValidationErrors(err); errs != nil {
+			t.Errorf("AsValidationErrors(%v) = %v, want nil", err, errs)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"errors"
+)
+
+var (
+	ErrHKDFSaltMissing = errors.New("hkdf salt is missing in token header")
+)
+
+// HKDFKeyfunc returns a Keyfunc for HMAC signed tokens, whose key is derived from secret using
+// HKDF (RFC 5869). The salt is read from the header parameter saltHeader, encoded as base64url
+// without padding, so that keys can be rotated by the issuer without distributing new secrets.
+// info binds the derived key to a context, e.g. the name of the application.
+//
+// The hash function of the token's signing method is used for HKDF, and the derived key has the
+// size of its output. Tokens that are not signed using HMAC are rejected. Use DeriveHMACKey to
+// derive the matching key for signing.
+func HKDFKeyfunc(secret []byte, saltHeader string, info []byte) Keyfunc {
+	return func(token *Token) (interface{}, error) {
+		method, ok := token.Method.(*SigningMethodHMAC)
+		if !ok {
+			return nil, ErrInvalidKeyType
+		}
+
+		encoded, ok := token.Header[saltHeader].(string)
+		if !ok || encoded == "" {
+			return nil, ErrHKDFSaltMissing
+		}
+
+		salt, err := DecodeSegment(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		return DeriveHMACKey(method, secret, salt, info), nil
+	}
+}
+
+// DeriveHMACKey derives a key for method from secret using HKDF (RFC 5869) with the hash function
+// of method. The key has the size of the hash function's output. See HKDFKeyfunc.
+func DeriveHMACKey(method *SigningMethodHMAC, secret, salt, info []byte) []byte {
+	return hkdf(method.Hash, secret, salt, info, method.Hash.Size())
+}
+
+// hkdf implements the extract and expand steps of RFC 5869 and returns length bytes of output
+// keying material. length must not exceed 255 times the output size of hash.
+func hkdf(hash crypto.Hash, secret, salt, info []byte, length int) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, hash.Size())
+	}
+
+	// Extract
+	extractor := hmac.New(hash.New, salt)
+	extractor.Write(secret)
+	prk := extractor.Sum(nil)
+
+	// Expand
+	expander := hmac.New(hash.New, prk)
+	var okm, t []byte
+	for i := byte(1); len(okm) < length; i++ {
+		expander.Reset()
+		expander.Write(t)
+		expander.Write(info)
+		expander.Write([]byte{i})
+		t = expander.Sum(nil)
+		okm = append(okm, t...)
+	}
+
+	return okm[:length]
+}
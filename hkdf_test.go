@@ -0,0 +1,70 @@
+package jwt_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestDeriveHMACKey(t *testing.T) {
+	// Test case 1 of https://datatracker.ietf.org/doc/html/rfc5869#appendix-A.1, truncated to the
+	// output size of SHA-256
+	secret := bytes.Repeat([]byte{0x0b}, 22)
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	want, _ := hex.DecodeString("3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf")
+
+	if got := jwt.DeriveHMACKey(jwt.SigningMethodHS256, secret, salt, info); !bytes.Equal(got, want) {
+		t.Errorf("DeriveHMACKey() = %x, want %x", got, want)
+	}
+
+	if got := jwt.DeriveHMACKey(jwt.SigningMethodHS512, secret, salt, info); len(got) != 64 {
+		t.Errorf("DeriveHMACKey() returned %d bytes, want 64", len(got))
+	}
+}
+
+func TestHKDFKeyfunc(t *testing.T) {
+	secret := []byte("master secret")
+	info := []byte("my-app")
+	salt := []byte("2021-11-20")
+	keyFunc := jwt.HKDFKeyfunc(secret, "salt", info)
+
+	token := jwt.New(jwt.SigningMethodHS384)
+	token.Header["salt"] = jwt.EncodeSegment(salt)
+	tokenString, err := token.SignedString(jwt.DeriveHMACKey(jwt.SigningMethodHS384, secret, salt, info))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = jwt.Parse(tokenString, keyFunc); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+
+	// A different salt yields a different key
+	token.Header["salt"] = jwt.EncodeSegment([]byte("2021-11-21"))
+	forged, err := token.SignedString(jwt.DeriveHMACKey(jwt.SigningMethodHS384, secret, salt, info))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = jwt.Parse(forged, keyFunc); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Errorf("Parse() error = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+	}
+
+	// The salt is required
+	delete(token.Header, "salt")
+	unsalted, err := token.SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = jwt.Parse(unsalted, keyFunc); !errors.Is(err, jwt.ErrHKDFSaltMissing) {
+		t.Errorf("Parse() error = %v, want %v", err, jwt.ErrHKDFSaltMissing)
+	}
+
+	// Only HMAC signed tokens are accepted
+	if _, err = jwt.Parse(signToken(jwt.MapClaims{}, jwt.SigningMethodRS256), keyFunc); !errors.Is(err, jwt.ErrInvalidKeyType) {
+		t.Errorf("Parse() error = %v, want %v", err, jwt.ErrInvalidKeyType)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"crypto"
 	"crypto/hmac"
 	"errors"
+	"hash"
+	"sync"
 )
 
 // SigningMethodHMAC implements the HMAC-SHA family of signing methods.
-// Expects key type of []byte for both signing and validation
+// Expects key type of []byte or *HMACKey for both signing and validation
 type SigningMethodHMAC struct {
 	Name string
 	Hash crypto.Hash
@@ -48,8 +50,7 @@ func (m *SigningMethodHMAC) Alg() string {
 // Verify implements token verification for the SigningMethod. Returns nil if the signature is valid.
 func (m *SigningMethodHMAC) Verify(signingString, signature string, key interface{}) error {
 	// Verify the key is the right type
-	keyBytes, ok := key.([]byte)
-	if !ok {
+	if _, ok := m.keyBytes(key); !ok {
 		return ErrInvalidKeyType
 	}
 
@@ -59,6 +60,16 @@ func (m *SigningMethodHMAC) Verify(signingString, signature string, key interfac
 		return err
 	}
 
+	return m.VerifyBytes(signingString, sig, key)
+}
+
+// VerifyBytes works like Verify, but takes the already decoded signature.
+func (m *SigningMethodHMAC) VerifyBytes(signingString string, sig []byte, key interface{}) error {
+	// Verify the key is the right type
+	if _, ok := m.keyBytes(key); !ok {
+		return ErrInvalidKeyType
+	}
+
 	// Can we use the specified hashing method?
 	if !m.Hash.Available() {
 		return ErrHashUnavailable
@@ -67,9 +78,7 @@ func (m *SigningMethodHMAC) Verify(signingString, signature string, key interfac
 	// This signing method is symmetric, so we validate the signature
 	// by reproducing the signature from the signing string and key, then
 	// comparing that against the provided signature.
-	hasher := hmac.New(m.Hash.New, keyBytes)
-	hasher.Write([]byte(signingString))
-	if !hmac.Equal(sig, hasher.Sum(nil)) {
+	if !hmac.Equal(sig, m.sum(signingString, key)) {
 		return ErrSignatureInvalid
 	}
 
@@ -78,18 +87,66 @@ func (m *SigningMethodHMAC) Verify(signingString, signature string, key interfac
 }
 
 // Sign implements token signing for the SigningMethod.
-// Key must be []byte
+// Key must be []byte or *HMACKey
 func (m *SigningMethodHMAC) Sign(signingString string, key interface{}) (string, error) {
-	if keyBytes, ok := key.([]byte); ok {
+	if _, ok := m.keyBytes(key); ok {
 		if !m.Hash.Available() {
 			return "", ErrHashUnavailable
 		}
 
+		return EncodeSegment(m.sum(signingString, key)), nil
+	}
+
+	return "", ErrInvalidKeyType
+}
+
+// HMACKey is a key for a HMAC signing method, which can be used instead of a []byte key, e.g. by
+// returning it from a Keyfunc. It reuses HMAC instances across operations, which saves setting up
+// the inner and outer hash state every time, e.g. in tight loops. The key is owned by the caller,
+// who creates it once per secret using NewHMACKey and drops it when the secret is rotated. It is
+// safe for concurrent use.
+type HMACKey struct {
+	hash crypto.Hash
+	key  []byte
+	pool sync.Pool
+}
+
+// NewHMACKey creates a HMACKey for signing and verifying with method. The key is copied.
+func NewHMACKey(method *SigningMethodHMAC, key []byte) *HMACKey {
+	k := &HMACKey{hash: method.Hash, key: append([]byte(nil), key...)}
+	k.pool.New = func() interface{} {
+		return hmac.New(k.hash.New, k.key)
+	}
+	return k
+}
+
+// keyBytes returns the secret of key, which must be a []byte or a *HMACKey for this signing method.
+func (m *SigningMethodHMAC) keyBytes(key interface{}) ([]byte, bool) {
+	switch k := key.(type) {
+	case []byte:
+		return k, true
+	case *HMACKey:
+		if k != nil && k.hash == m.Hash {
+			return k.key, true
+		}
+	}
+	return nil, false
+}
+
+// sum computes the HMAC of signingString with key. The HMAC instances of a *HMACKey are reused.
+func (m *SigningMethodHMAC) sum(signingString string, key interface{}) []byte {
+	k, ok := key.(*HMACKey)
+	if !ok {
+		keyBytes, _ := m.keyBytes(key)
 		hasher := hmac.New(m.Hash.New, keyBytes)
 		hasher.Write([]byte(signingString))
-
-		return EncodeSegment(hasher.Sum(nil)), nil
+		return hasher.Sum(nil)
 	}
 
-	return "", ErrInvalidKeyType
+	hasher := k.pool.Get().(hash.Hash)
+	defer k.pool.Put(hasher)
+
+	hasher.Reset()
+	hasher.Write([]byte(signingString))
+	return hasher.Sum(nil)
 }
@@ -1,8 +1,11 @@
 package jwt_test
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"io/ioutil"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -63,6 +66,25 @@ func TestHMACVerify(t *testing.T) {
 	}
 }
 
+func TestHMACVerifyBytes(t *testing.T) {
+	for _, data := range hmacTestData {
+		parts := strings.Split(data.tokenString, ".")
+		sig, err := jwt.DecodeSegment(parts[2])
+		if err != nil {
+			t.Fatalf("[%v] Error decoding signature: %v", data.name, err)
+		}
+
+		method := jwt.GetSigningMethod(data.alg).(*jwt.SigningMethodHMAC)
+		err = method.VerifyBytes(strings.Join(parts[0:2], "."), sig, hmacTestKey)
+		if data.valid && err != nil {
+			t.Errorf("[%v] Error while verifying key: %v", data.name, err)
+		}
+		if !data.valid && err == nil {
+			t.Errorf("[%v] Invalid key passed validation", data.name)
+		}
+	}
+}
+
 func TestHMACSign(t *testing.T) {
 	for _, data := range hmacTestData {
 		if data.valid {
@@ -79,6 +101,54 @@ func TestHMACSign(t *testing.T) {
 	}
 }
 
+func TestHMACKey(t *testing.T) {
+	const signingString = "eyJhbGciOiJIUzI1NiJ9.eyJmb28iOiJiYXIifQ"
+
+	hasher := hmac.New(sha256.New, hmacTestKey)
+	hasher.Write([]byte(signingString))
+	want := jwt.EncodeSegment(hasher.Sum(nil))
+
+	key := jwt.NewHMACKey(jwt.SigningMethodHS256, hmacTestKey)
+
+	// HMAC instances are reused concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				sig, err := jwt.SigningMethodHS256.Sign(signingString, key)
+				if err != nil || sig != want {
+					t.Errorf("Sign() = %v, %v, want %v", sig, err, want)
+				}
+				if err = jwt.SigningMethodHS256.Verify(signingString, want, key); err != nil {
+					t.Errorf("Verify() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The key is bound to its signing method
+	if _, err := jwt.SigningMethodHS512.Sign(signingString, key); err != jwt.ErrInvalidKeyType {
+		t.Errorf("Sign() error = %v, want %v", err, jwt.ErrInvalidKeyType)
+	}
+	if err := jwt.SigningMethodHS512.Verify(signingString, want, key); err != jwt.ErrInvalidKeyType {
+		t.Errorf("Verify() error = %v, want %v", err, jwt.ErrInvalidKeyType)
+	}
+
+	// The key can be returned by a Keyfunc
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"}).SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return key, nil }, jwt.WithStrictKeyTypeCheck())
+	if err != nil || !token.Valid {
+		t.Errorf("Parse() error = %v", err)
+	}
+}
+
 func BenchmarkHS256Signing(b *testing.B) {
 	benchmarkSigning(b, jwt.SigningMethodHS256, hmacTestKey)
 }
@@ -90,3 +160,7 @@ func BenchmarkHS384Signing(b *testing.B) {
 func BenchmarkHS512Signing(b *testing.B) {
 	benchmarkSigning(b, jwt.SigningMethodHS512, hmacTestKey)
 }
+
+func BenchmarkHS256SigningHMACKey(b *testing.B) {
+	benchmarkSigning(b, jwt.SigningMethodHS256, jwt.NewHMACKey(jwt.SigningMethodHS256, hmacTestKey))
+}
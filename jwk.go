@@ -0,0 +1,70 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+)
+
+// JWKThumbprint computes the JSON Web Key thumbprint of a public key as specified in RFC 7638,
+// using SHA-256, encoded as base64url without padding. It can be used to pin keys, e.g. by
+// comparing the thumbprint of a key embedded in a token against a list of trusted thumbprints.
+// Supported keys are *rsa.PublicKey, *ecdsa.PublicKey on the curves P-256, P-384 and P-521, and
+// ed25519.PublicKey.
+func JWKThumbprint(key interface{}) (string, error) {
+	var members map[string]string
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if k.N == nil {
+			return "", ErrInvalidKey
+		}
+		members = map[string]string{
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		if k.Curve == nil || k.X == nil || k.Y == nil {
+			return "", ErrInvalidKey
+		}
+		params := k.Curve.Params()
+		switch params.Name {
+		case "P-256", "P-384", "P-521":
+		default:
+			return "", ErrInvalidKeyType
+		}
+		// The coordinates are encoded using the full size of the curve (RFC 7518, section 6.2.1.2)
+		size := (params.BitSize + 7) / 8
+		members = map[string]string{
+			"crv": params.Name,
+			"kty": "EC",
+			"x":   base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size))),
+			"y":   base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size))),
+		}
+	case ed25519.PublicKey:
+		if len(k) != ed25519.PublicKeySize {
+			return "", ErrInvalidKey
+		}
+		members = map[string]string{
+			"crv": "Ed25519",
+			"kty": "OKP",
+			"x":   base64.RawURLEncoding.EncodeToString(k),
+		}
+	default:
+		return "", ErrInvalidKeyType
+	}
+
+	// Maps are marshalled with sorted keys and without whitespace, as required by RFC 7638
+	b, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,73 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestJWKThumbprint(t *testing.T) {
+	// RFC 7638, section 3.1
+	rsaKey, err := jwt.ParseRSAPublicKeyFromJWK("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw", "AQAB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RFC 8037, appendix A.3
+	edKey, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		key        interface{}
+		thumbprint string
+		err        error
+	}{
+		{"RSA", rsaKey, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", nil},
+		{"Ed25519", ed25519.PublicKey(edKey), "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", nil},
+		{"Ed25519 of invalid size", ed25519.PublicKey(edKey[:16]), "", jwt.ErrInvalidKey},
+		{"private key", jwtTestRSAPrivateKey, "", jwt.ErrInvalidKeyType},
+		{"HMAC key", hmacTestKey, "", jwt.ErrInvalidKeyType},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			thumbprint, err := jwt.JWKThumbprint(tc.key)
+			if thumbprint != tc.thumbprint {
+				t.Errorf("JWKThumbprint() = %v, want %v", thumbprint, tc.thumbprint)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("JWKThumbprint() error = %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestJWKThumbprint_EC(t *testing.T) {
+	publicKey := jwtTestEC256PublicKey.(*ecdsa.PublicKey)
+	thumbprint, err := jwt.JWKThumbprint(publicKey)
+	if err != nil {
+		t.Fatalf("JWKThumbprint() error = %v", err)
+	}
+
+	// The thumbprint is independent of the representation of the key
+	x := base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, 32)))
+	y := base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, 32)))
+	key, err := jwt.ParseECPublicKeyFromJWK("P-256", x, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other, _ := jwt.JWKThumbprint(key); other != thumbprint {
+		t.Errorf("JWKThumbprint() = %v, want %v", other, thumbprint)
+	}
+
+	if other, _ := jwt.JWKThumbprint(&jwtTestRSAPrivateKey.PublicKey); other == thumbprint {
+		t.Errorf("JWKThumbprint() of different keys are equal")
+	}
+}
@@ -0,0 +1,25 @@
+// Package jwttest provides helpers to mint tokens in tests with a single line.
+//
+// The helpers panic on error and must therefore only be used in tests.
+package jwttest
+
+import (
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// MustSign creates a token with the specified signing method and claims, signs it with key and
+// returns the signed token. MustSign panics if the token cannot be signed.
+func MustSign(method jwt.SigningMethod, claims jwt.Claims, key interface{}) string {
+	s, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		panic("jwttest: could not sign token: " + err.Error())
+	}
+
+	return s
+}
+
+// NewHMACToken returns a token with the specified claims, signed using HS256 and secret.
+// NewHMACToken panics if the token cannot be signed.
+func NewHMACToken(claims jwt.Claims, secret []byte) string {
+	return MustSign(jwt.SigningMethodHS256, claims, secret)
+}
@@ -0,0 +1,32 @@
+package jwttest_test
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang-jwt/jwt/v4/jwttest"
+)
+
+func TestNewHMACToken(t *testing.T) {
+	secret := []byte("secret")
+
+	tokenString := jwttest.NewHMACToken(jwt.MapClaims{"sub": "user"}, secret)
+
+	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) { return secret, nil })
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if token.Method != jwt.SigningMethodHS256 || token.Claims.(jwt.MapClaims)["sub"] != "user" {
+		t.Errorf("Parse() = %+v, want HS256 token with sub claim", token)
+	}
+}
+
+func TestMustSign_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustSign() did not panic for an invalid key")
+		}
+	}()
+
+	jwttest.MustSign(jwt.SigningMethodRS256, jwt.MapClaims{}, []byte("not an RSA key"))
+}
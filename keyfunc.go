@@ -0,0 +1,104 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrKeyIDUnknown = errors.New("kid is unknown")
+)
+
+// HMACKeyfunc returns a Keyfunc, which looks up the HMAC secret for a token by its "kid" header in
+// secrets, e.g. for per-tenant secrets. The map is copied, so later changes to secrets have no
+// effect. Tokens without a known "kid" are rejected, as are tokens that are not signed using HMAC,
+// including those using the "none" signing method.
+func HMACKeyfunc(secrets map[string][]byte) Keyfunc {
+	keys := make(map[string][]byte, len(secrets))
+	for kid, secret := range secrets {
+		keys[kid] = secret
+	}
+
+	return func(token *Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *SigningMethodHMAC:
+		case *signingMethodNone:
+			return nil, NoneSignatureTypeDisallowedError
+		default:
+			return nil, ErrInvalidKeyType
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, ErrTokenKeyIDMissing
+		}
+
+		secret, ok := keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrKeyIDUnknown, kid)
+		}
+
+		return secret, nil
+	}
+}
+
+// RequireAlg returns a Keyfunc, which rejects tokens whose algorithm is not alg before looking up
+// the key using inner, e.g. to pin the algorithm of a Keyfunc that selects keys by "kid". This is a
+// safeguard in addition to WithValidMethods. Like WithValidMethods, it compares the canonical name
+// of the signing method, so that aliases registered with RegisterAlgorithmAlias are accepted.
+func RequireAlg(alg string, inner Keyfunc) Keyfunc {
+	return func(token *Token) (interface{}, error) {
+		if a := token.Alg(); a != alg {
+			return nil, NewValidationError(fmt.Sprintf("signing method %v is invalid", a), ValidationErrorSignatureInvalid)
+		}
+
+		return inner(token)
+	}
+}
+
+// maxCachedKeys is the maximum number of keys cached by a Keyfunc returned by CachedKeyfunc.
+const maxCachedKeys = 100
+
+// CachedKeyfunc returns a Keyfunc, which caches the keys returned by inner, e.g. to avoid parsing a
+// PEM encoded key for every token. Keys are cached by the canonical algorithm and the "kid" header
+// of the token, so inner must not select keys based on anything else, such as the claims. Errors are not cached.
+// At most 100 keys are cached; once the limit is reached, the oldest key is evicted. The returned
+// Keyfunc is safe for concurrent use.
+func CachedKeyfunc(inner Keyfunc) Keyfunc {
+	var (
+		mu    sync.Mutex
+		keys  = make(map[string]interface{})
+		order []string
+	)
+
+	return func(token *Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		cacheKey := token.Alg() + "\x00" + kid
+
+		mu.Lock()
+		key, ok := keys[cacheKey]
+		mu.Unlock()
+		if ok {
+			return key, nil
+		}
+
+		key, err := inner(token)
+		if err != nil || isNilKey(key) {
+			return key, err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if _, ok := keys[cacheKey]; !ok {
+			if len(order) == maxCachedKeys {
+				delete(keys, order[0])
+				order = order[1:]
+			}
+			order = append(order, cacheKey)
+		}
+		keys[cacheKey] = key
+
+		return key, nil
+	}
+}
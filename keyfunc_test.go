@@ -0,0 +1,168 @@
+package jwt_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestHMACKeyfunc(t *testing.T) {
+	secrets := map[string][]byte{
+		"tenant-a": []byte("secret a"),
+		"tenant-b": []byte("secret b"),
+	}
+	keyFunc := jwt.HMACKeyfunc(secrets)
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"foo": "bar"})
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	// Changes to the map after creating the Keyfunc have no effect
+	secrets["tenant-c"] = []byte("secret c")
+
+	tests := []struct {
+		name        string
+		tokenString string
+		err         error
+	}{
+		{"known kid", sign(jwt.SigningMethodHS256, "tenant-a", []byte("secret a")), nil},
+		{"other tenant's secret", sign(jwt.SigningMethodHS256, "tenant-b", []byte("secret a")), jwt.ErrTokenSignatureInvalid},
+		{"unknown kid", sign(jwt.SigningMethodHS256, "tenant-c", []byte("secret c")), jwt.ErrKeyIDUnknown},
+		{"missing kid", sign(jwt.SigningMethodHS256, "", []byte("secret a")), jwt.ErrTokenKeyIDMissing},
+		{"none", sign(jwt.SigningMethodNone, "tenant-a", jwt.UnsafeAllowNoneSignatureType), jwt.NoneSignatureTypeDisallowedError},
+		{"asymmetric", sign(jwt.SigningMethodRS256, "tenant-a", jwtTestRSAPrivateKey), jwt.ErrInvalidKeyType},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := jwt.Parse(tc.tokenString, keyFunc)
+			if tc.err == nil && err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Fatalf("Parse() error = %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestCachedKeyfunc(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	keyFunc := jwt.CachedKeyfunc(func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		mu.Lock()
+		calls[kid]++
+		mu.Unlock()
+		if kid == "unknown" {
+			return nil, jwt.ErrKeyIDUnknown
+		}
+		return []byte("secret " + kid), nil
+	})
+
+	newToken := func(kid string) *jwt.Token {
+		return jwt.New(jwt.SigningMethodHS256, jwt.WithHeader(map[string]interface{}{"kid": kid}))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, kid := range []string{"a", "b", "unknown"} {
+				key, err := keyFunc(newToken(kid))
+				if kid == "unknown" {
+					if !errors.Is(err, jwt.ErrKeyIDUnknown) {
+						t.Errorf("keyFunc(%s) error = %v, want %v", kid, err, jwt.ErrKeyIDUnknown)
+					}
+					continue
+				}
+				if string(key.([]byte)) != "secret "+kid {
+					t.Errorf("keyFunc(%s) = %s", kid, key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Keys are resolved again only until they are cached, errors are never cached
+	if calls["a"] > 10 || calls["b"] > 10 || calls["unknown"] != 10 {
+		t.Fatalf("calls = %v", calls)
+	}
+	before := calls["a"]
+	if _, err := keyFunc(newToken("a")); err != nil || calls["a"] != before {
+		t.Errorf("keyFunc(a) error = %v, calls = %d, want cached key", err, calls["a"])
+	}
+
+	// Once the cache is full, the oldest keys are evicted
+	for i := 0; i < 100; i++ {
+		if _, err := keyFunc(newToken(fmt.Sprintf("kid-%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := keyFunc(newToken("a")); err != nil || calls["a"] != before+1 {
+		t.Errorf("keyFunc(a) error = %v, calls = %d, want evicted key", err, calls["a"])
+	}
+	if _, err := keyFunc(newToken("kid-99")); err != nil || calls["kid-99"] != 1 {
+		t.Errorf("keyFunc(kid-99) error = %v, calls = %d, want cached key", err, calls["kid-99"])
+	}
+
+	// Aliases share the key of their canonical algorithm
+	jwt.RegisterAlgorithmAlias("RSA256", "RS256")
+	aliased := &jwt.Token{Method: jwt.GetSigningMethod("RSA256"), Header: map[string]interface{}{"alg": "RSA256", "kid": "rsa"}}
+	canonical := &jwt.Token{Method: jwt.SigningMethodRS256, Header: map[string]interface{}{"alg": "RS256", "kid": "rsa"}}
+	for _, token := range []*jwt.Token{aliased, canonical} {
+		if _, err := keyFunc(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls["rsa"] != 1 {
+		t.Errorf("calls = %d, want key cached across aliases", calls["rsa"])
+	}
+}
+
+func TestRequireAlg(t *testing.T) {
+	keyFunc := jwt.RequireAlg("RS256", defaultKeyFunc)
+
+	if _, err := jwt.Parse(signToken(jwt.MapClaims{"foo": "bar"}, jwt.SigningMethodRS256), keyFunc); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+
+	// The key would be suitable for HS256, but the Keyfunc rejects the algorithm
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"}).SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	called := false
+	keyFunc = jwt.RequireAlg("RS256", func(t *jwt.Token) (interface{}, error) {
+		called = true
+		return hmacTestKey, nil
+	})
+	if _, err = jwt.Parse(tokenString, keyFunc); !errors.Is(err, jwt.ErrTokenSignatureInvalid) || called {
+		t.Errorf("Parse() error = %v, inner called = %v, want %v", err, called, jwt.ErrTokenSignatureInvalid)
+	}
+
+	// Aliases are accepted for their canonical algorithm
+	jwt.RegisterAlgorithmAlias("RSA256", "RS256")
+	parts := strings.Split(signToken(jwt.MapClaims{"foo": "bar"}, jwt.SigningMethodRS256), ".")
+	header := jwt.EncodeSegment([]byte(`{"alg":"RSA256","typ":"JWT"}`))
+	sig, err := jwt.SigningMethodRS256.Sign(header+"."+parts[1], jwtTestRSAPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = jwt.Parse(header+"."+parts[1]+"."+sig, jwt.RequireAlg("RS256", defaultKeyFunc)); err != nil {
+		t.Errorf("Parse() with alias error = %v", err)
+	}
+}
@@ -3,6 +3,12 @@ package jwt
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 	// "fmt"
 )
@@ -14,7 +20,16 @@ type MapClaims map[string]interface{}
 // VerifyAudience Compares the aud claim against cmp.
 // If required is false, this method will return true if the value matches or is unset
 func (m MapClaims) VerifyAudience(cmp string, req bool) bool {
-	var aud []string
+	aud, ok := m.audience()
+	if !ok {
+		return false
+	}
+	return verifyAud(aud, cmp, req)
+}
+
+// audience returns the aud claim as a list of strings. ok is false, if the aud claim
+// is a list that contains values other than strings.
+func (m MapClaims) audience() (aud []string, ok bool) {
 	switch v := m["aud"].(type) {
 	case string:
 		aud = append(aud, v)
@@ -24,12 +39,31 @@ func (m MapClaims) VerifyAudience(cmp string, req bool) bool {
 		for _, a := range v {
 			vs, ok := a.(string)
 			if !ok {
-				return false
+				return nil, false
 			}
 			aud = append(aud, vs)
 		}
 	}
-	return verifyAud(aud, cmp, req)
+	return aud, true
+}
+
+// date returns the claim name as a time. ok is false, if the claim is not a number.
+// If the claim is not set, the returned time is nil.
+func (m MapClaims) date(name string) (t *time.Time, ok bool) {
+	switch v := m[name].(type) {
+	case nil:
+		return nil, true
+	case float64:
+		return &newNumericDateFromSeconds(v).Time, true
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, false
+		}
+		return &newNumericDateFromSeconds(f).Time, true
+	}
+
+	return nil, false
 }
 
 // VerifyExpiresAt compares the exp claim against cmp (cmp <= exp).
@@ -126,8 +160,13 @@ func (m MapClaims) Valid() error {
 	now := TimeFunc().Unix()
 
 	if !m.VerifyExpiresAt(now, false) {
-		// TODO(oxisto): this should be replaced with ErrTokenExpired
-		vErr.Inner = errors.New("Token is expired")
+		if exp, ok := m.date("exp"); ok && exp != nil {
+			t := time.Unix(now, 0)
+			vErr.Inner = &TokenExpiredError{ExpiresAt: *exp, Now: t, ExpiredBy: t.Sub(*exp)}
+		} else {
+			// TODO(oxisto): this should be replaced with ErrTokenExpired
+			vErr.Inner = errors.New("Token is expired")
+		}
 		vErr.Errors |= ValidationErrorExpired
 	}
 
@@ -138,8 +177,13 @@ func (m MapClaims) Valid() error {
 	}
 
 	if !m.VerifyNotBefore(now, false) {
-		// TODO(oxisto): this should be replaced with ErrTokenNotValidYet
-		vErr.Inner = errors.New("Token is not valid yet")
+		if nbf, ok := m.date("nbf"); ok && nbf != nil {
+			t := time.Unix(now, 0)
+			vErr.Inner = &TokenNotValidYetError{NotBefore: *nbf, Now: t, ValidIn: nbf.Sub(t)}
+		} else {
+			// TODO(oxisto): this should be replaced with ErrTokenNotValidYet
+			vErr.Inner = errors.New("Token is not valid yet")
+		}
 		vErr.Errors |= ValidationErrorNotValidYet
 	}
 
@@ -149,3 +193,201 @@ func (m MapClaims) Valid() error {
 
 	return vErr
 }
+
+// Keys returns the names of all claims in m, sorted in ascending order.
+func (m MapClaims) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Flatten returns the claims of m with nested objects and arrays flattened into a single map.
+// The keys of nested values are joined with a dot and array elements are addressed by their
+// index, e.g. {"address":{"country":"US"},"roles":["admin"]} becomes
+// {"address.country":"US","roles.0":"admin"}. Empty objects and arrays are kept as they are.
+func (m MapClaims) Flatten() map[string]interface{} {
+	flat := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		flatten(flat, k, v)
+	}
+
+	return flat
+}
+
+// flatten adds v to flat under key, descending into nested objects and arrays.
+func flatten(flat map[string]interface{}, key string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 {
+			for k, nested := range v {
+				flatten(flat, key+"."+k, nested)
+			}
+			return
+		}
+	case MapClaims:
+		flatten(flat, key, map[string]interface{}(v))
+		return
+	case []interface{}:
+		if len(v) > 0 {
+			for i, nested := range v {
+				flatten(flat, key+"."+strconv.Itoa(i), nested)
+			}
+			return
+		}
+	case []string:
+		if len(v) > 0 {
+			for i, nested := range v {
+				flat[key+"."+strconv.Itoa(i)] = nested
+			}
+			return
+		}
+	}
+
+	flat[key] = v
+}
+
+// Scopes returns the OAuth 2.0 scopes of the token. They are read from the space-delimited "scope"
+// claim (RFC 8693) or, if it is not set, from the "scp" claim, which is commonly a list of strings.
+// Values that are not strings are ignored. If neither claim is set, an empty slice is returned.
+func (m MapClaims) Scopes() []string {
+	scopes := []string{}
+
+	v, ok := m["scope"]
+	if !ok {
+		v = m["scp"]
+	}
+
+	switch v := v.(type) {
+	case string:
+		scopes = append(scopes, strings.Fields(v)...)
+	case []string:
+		scopes = append(scopes, v...)
+	case []interface{}:
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+
+	return scopes
+}
+
+// GetIssuer returns the iss claim. If the claim is not set, an empty string is returned.
+// ErrInvalidType is returned, if the claim is not a string.
+func (m MapClaims) GetIssuer() (string, error) {
+	return m.parseString("iss")
+}
+
+// GetSubject returns the sub claim. If the claim is not set, an empty string is returned.
+// ErrInvalidType is returned, if the claim is not a string.
+func (m MapClaims) GetSubject() (string, error) {
+	return m.parseString("sub")
+}
+
+// GetAudience returns the aud claim, which can either be a single string or a list of strings.
+// If the claim is not set, nil is returned. ErrInvalidType is returned, if the claim is neither,
+// e.g. a list containing non-string values, rather than picking some of its entries. If the claim
+// occurred more than once in the token, its last occurrence was decoded; use
+// WithoutDuplicateClaims to reject such tokens instead.
+func (m MapClaims) GetAudience() (ClaimStrings, error) {
+	switch m["aud"].(type) {
+	case nil, string, []string, []interface{}:
+		if aud, ok := m.audience(); ok {
+			return aud, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: aud", ErrInvalidType)
+}
+
+// GetID returns the jti claim. If the claim is not set, an empty string is returned.
+// ErrInvalidType is returned, if the claim is not a string.
+func (m MapClaims) GetID() (string, error) {
+	return m.parseString("jti")
+}
+
+// parseString returns the claim key as a string.
+func (m MapClaims) parseString(key string) (string, error) {
+	switch v := m[key].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrInvalidType, key)
+}
+
+// GetBool returns the claim name as a boolean, e.g. for an "email_verified" claim. Besides JSON
+// booleans, the strings "true" and "false" are accepted for issuers that encode booleans as
+// strings. If the claim is not set, false is returned. ErrInvalidType is returned for other values.
+func (m MapClaims) GetBool(name string) (bool, error) {
+	switch v := m[name].(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case string:
+		switch v {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+
+	return false, fmt.Errorf("%w: %s", ErrInvalidType, name)
+}
+
+// GetTime returns the claim name interpreted as a numeric date, i.e. the number of seconds since
+// the epoch, e.g. for custom time claims. Like the registered time claims, it is truncated to
+// TimePrecision. If the claim is not set, the zero time is returned.
+// ErrInvalidType is returned, if the claim is not a number.
+func (m MapClaims) GetTime(name string) (time.Time, error) {
+	t, ok := m.date(name)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidType, name)
+	}
+	if t == nil {
+		return time.Time{}, nil
+	}
+
+	return *t, nil
+}
+
+// GetBigInt returns the claim name as an integer of arbitrary size. Large integers can only be
+// retrieved without loss of precision, if the token was parsed using WithJSONNumber, since they
+// are otherwise decoded as float64. If the claim is not set, nil is returned. ErrInvalidType is
+// returned, if the claim is not an integer.
+func (m MapClaims) GetBigInt(name string) (*big.Int, error) {
+	switch v := m[name].(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		// Use a big.Rat, so that exponent notation (e.g. 1e30) is supported as well
+		if r, ok := new(big.Rat).SetString(string(v)); ok && r.IsInt() {
+			return new(big.Int).Set(r.Num()), nil
+		}
+	case float64:
+		if math.IsNaN(v) {
+			break
+		}
+		if f := big.NewFloat(v); f.IsInt() {
+			i, _ := f.Int(nil)
+			return i, nil
+		}
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case *big.Int:
+		return new(big.Int).Set(v), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrInvalidType, name)
+}
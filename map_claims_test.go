@@ -1,6 +1,11 @@
 package jwt
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -44,6 +49,10 @@ func TestVerifyAud(t *testing.T) {
 		// Required = false
 		{Name: "Empty []String Aud without match required", MapClaims: MapClaims{"aud": []string{""}}, Expected: false, Required: true, Comparison: "example.com"},
 
+		// Empty entries never match
+		{Name: "[]String Aud with empty entry and empty cmp required", MapClaims: MapClaims{"aud": []string{"", "example.com"}}, Expected: false, Required: true, Comparison: ""},
+		{Name: "[]interface{} Aud with empty entry and empty cmp required", MapClaims: MapClaims{"aud": []interface{}{"", "example.com"}}, Expected: false, Required: true, Comparison: ""},
+
 		// []interface{}
 		{Name: "Empty []interface{} Aud without match required", MapClaims: MapClaims{"aud": nilListInterface}, Expected: true, Required: false, Comparison: "example.com"},
 		{Name: "[]interface{} Aud wit match required", MapClaims: MapClaims{"aud": []interface{}{"a", "foo", "example.com"}}, Expected: true, Required: true, Comparison: "example.com"},
@@ -121,3 +130,201 @@ func TestMapClaimsVerifyExpiresAtExpire(t *testing.T) {
 		t.Fatalf("Failed to verify claims, wanted: %v got %v", want, got)
 	}
 }
+
+func TestMapClaims_GetBigInt(t *testing.T) {
+	const large = "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+
+	var m MapClaims
+	dec := json.NewDecoder(bytes.NewBufferString(`{"large": ` + large + `, "exp": 1e3, "frac": 1.5, "str": "1"}`))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		t.Fatal(err)
+	}
+	m["float"] = float64(1 << 40)
+
+	tests := []struct {
+		name string
+		want string
+		err  error
+	}{
+		{name: "large", want: large},
+		{name: "exp", want: "1000"},
+		{name: "float", want: "1099511627776"},
+		{name: "frac", err: ErrInvalidType},
+		{name: "str", err: ErrInvalidType},
+		{name: "missing"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := m.GetBigInt(tc.name)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("GetBigInt() error = %v, want %v", err, tc.err)
+			}
+			if tc.want == "" {
+				if got != nil {
+					t.Errorf("GetBigInt() = %v, want nil", got)
+				}
+				return
+			}
+			want, _ := new(big.Int).SetString(tc.want, 10)
+			if got == nil || got.Cmp(want) != 0 {
+				t.Errorf("GetBigInt() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMapClaims_Flatten(t *testing.T) {
+	var m MapClaims
+	if err := json.Unmarshal([]byte(`{"sub":"foo","address":{"country":"US","geo":{"lat":1.5}},"roles":["admin",{"name":"ops"}],"empty":{},"none":[]}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	m["aud"] = []string{"a", "b"}
+
+	want := map[string]interface{}{
+		"sub":             "foo",
+		"address.country": "US",
+		"address.geo.lat": 1.5,
+		"roles.0":         "admin",
+		"roles.1.name":    "ops",
+		"empty":           map[string]interface{}{},
+		"none":            []interface{}{},
+		"aud.0":           "a",
+		"aud.1":           "b",
+	}
+	if got := m.Flatten(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Flatten() = %v, want %v", got, want)
+	}
+}
+
+func TestMapClaims_GetAudience(t *testing.T) {
+	tests := []struct {
+		name string
+		aud  interface{}
+		want ClaimStrings
+		err  error
+	}{
+		{name: "missing"},
+		{name: "string", aud: "a", want: ClaimStrings{"a"}},
+		{name: "list", aud: []interface{}{"a", "b"}, want: ClaimStrings{"a", "b"}},
+		{name: "mixed list", aud: []interface{}{"a", 1.0}, err: ErrInvalidType},
+		{name: "nested list", aud: []interface{}{"a", []interface{}{"b"}}, err: ErrInvalidType},
+		{name: "number", aud: 1.0, err: ErrInvalidType},
+		{name: "object", aud: map[string]interface{}{"aud": "a"}, err: ErrInvalidType},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := MapClaims{}
+			if tc.aud != nil {
+				m["aud"] = tc.aud
+			}
+
+			got, err := m.GetAudience()
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("GetAudience() error = %v, want %v", err, tc.err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("GetAudience() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMapClaims_Scopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims MapClaims
+		want   []string
+	}{
+		{"missing", MapClaims{}, []string{}},
+		{"scope", MapClaims{"scope": "read  write\tadmin"}, []string{"read", "write", "admin"}},
+		{"empty scope", MapClaims{"scope": ""}, []string{}},
+		{"scp list", MapClaims{"scp": []interface{}{"read", 1.0, "write"}}, []string{"read", "write"}},
+		{"scp string list", MapClaims{"scp": []string{"read"}}, []string{"read"}},
+		{"scp string", MapClaims{"scp": "read write"}, []string{"read", "write"}},
+		{"scope takes precedence", MapClaims{"scope": "read", "scp": []interface{}{"write"}}, []string{"read"}},
+		{"invalid type", MapClaims{"scope": 1.0}, []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.claims.Scopes(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Scopes() = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMapClaims_GetTime(t *testing.T) {
+	m := MapClaims{
+		"float":  float64(1500000000),
+		"frac":   1500000000.5,
+		"number": json.Number("1500000000"),
+		"str":    "1500000000",
+		"bad":    json.Number("soon"),
+	}
+
+	tests := []struct {
+		name string
+		want time.Time
+		err  error
+	}{
+		{name: "float", want: time.Unix(1500000000, 0)},
+		{name: "frac", want: time.Unix(1500000000, 0)}, // truncated to TimePrecision
+		{name: "number", want: time.Unix(1500000000, 0)},
+		{name: "str", err: ErrInvalidType},
+		{name: "bad", err: ErrInvalidType},
+		{name: "missing"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := m.GetTime(tc.name)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("GetTime() error = %v, want %v", err, tc.err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("GetTime() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMapClaims_GetBool(t *testing.T) {
+	m := MapClaims{
+		"email_verified": true,
+		"phone_verified": false,
+		"legacy_true":    "true",
+		"legacy_false":   "false",
+		"capitalized":    "True",
+		"number":         float64(1),
+	}
+
+	tests := []struct {
+		name string
+		want bool
+		err  error
+	}{
+		{name: "email_verified", want: true},
+		{name: "phone_verified", want: false},
+		{name: "legacy_true", want: true},
+		{name: "legacy_false", want: false},
+		{name: "capitalized", err: ErrInvalidType},
+		{name: "number", err: ErrInvalidType},
+		{name: "missing"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := m.GetBool(tc.name)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("GetBool() error = %v, want %v", err, tc.err)
+			}
+			if got != tc.want {
+				t.Errorf("GetBool() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
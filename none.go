@@ -2,6 +2,12 @@ package jwt
 
 // SigningMethodNone implements the none signing method.  This is required by the spec
 // but you probably should never use it.
+//
+// Tokens using the none signing method are unsigned, so in compact serialization they
+// consist of the header and claims followed by a trailing dot and an empty signature, i.e.
+// "header.claims.". The trailing dot is required by RFC 7519, section 6.1; tokens without
+// it ("header.claims") are rejected as malformed, and tokens with a non-empty signature are
+// rejected as invalid.
 var SigningMethodNone *signingMethodNone
 
 const UnsafeAllowNoneSignatureType unsafeNoneMagicConstant = "none signing method allowed"
@@ -43,6 +49,11 @@ func (m *signingMethodNone) Verify(signingString, signature string, key interfac
 	return nil
 }
 
+// VerifyBytes works like Verify, but takes the already decoded signature, which must be empty.
+func (m *signingMethodNone) VerifyBytes(signingString string, sig []byte, key interface{}) error {
+	return m.Verify(signingString, EncodeSegment(sig), key)
+}
+
 // Only allow 'none' signing if UnsafeAllowNoneSignatureType is specified as the key
 func (m *signingMethodNone) Sign(signingString string, key interface{}) (string, error) {
 	if _, ok := key.(unsafeNoneMagicConstant); ok {
@@ -1,6 +1,7 @@
 package jwt_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -71,3 +72,77 @@ func TestNoneSign(t *testing.T) {
 		}
 	}
 }
+
+func TestNoneParseShapes(t *testing.T) {
+	allowNone := func(t *jwt.Token) (interface{}, error) { return jwt.UnsafeAllowNoneSignatureType, nil }
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"foo": "bar"}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(tokenString, ".") {
+		t.Fatalf("SignedString() = %q, want trailing dot", tokenString)
+	}
+
+	tests := []struct {
+		name        string
+		tokenString string
+		err         error
+	}{
+		{"trailing dot", tokenString, nil},
+		{"without trailing dot", strings.TrimSuffix(tokenString, "."), jwt.ErrTokenMalformed},
+		{"non-empty signature", tokenString + "c2ln", jwt.ErrTokenSignatureInvalid},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := jwt.Parse(tc.tokenString, allowNone, jwt.WithoutClaimsValidation())
+			if tc.err == nil && err != nil {
+				t.Errorf("Parse() unexpected error = %v", err)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Errorf("Parse() error = %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestNoneParseWithValidMethods(t *testing.T) {
+	allowNone := func(t *jwt.Token) (interface{}, error) { return jwt.UnsafeAllowNoneSignatureType, nil }
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"foo": "bar"}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		methods []string
+		err     error
+	}{
+		{"none not listed", []string{"RS256"}, jwt.ErrTokenSignatureInvalid},
+		{"empty list", []string{}, jwt.ErrTokenSignatureInvalid},
+		{"none listed", []string{"RS256", "none"}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			keyfuncCalled := false
+			keyFunc := func(t *jwt.Token) (interface{}, error) {
+				keyfuncCalled = true
+				return allowNone(t)
+			}
+
+			_, err := jwt.Parse(tokenString, keyFunc, jwt.WithoutClaimsValidation(), jwt.WithValidMethods(tc.methods))
+			if tc.err == nil && err != nil {
+				t.Errorf("Parse() unexpected error = %v", err)
+			}
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("Parse() error = %v, want %v", err, tc.err)
+				}
+				if keyfuncCalled {
+					t.Errorf("Keyfunc was called for a refused signing method")
+				}
+			}
+		})
+	}
+}
@@ -2,9 +2,16 @@ package jwt
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"runtime/debug"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type Parser struct {
@@ -22,6 +29,110 @@ type Parser struct {
 	//
 	// Deprecated: In future releases, this field will not be exported anymore and should be set with an option to NewParser instead.
 	SkipClaimsValidation bool
+
+	// If populated, the "iss" claim must match one of these values.
+	issuers []string
+
+	// If verifyAudience is set, the "aud" claim must contain audience.
+	audience       string
+	verifyAudience bool
+
+	// Reject tokens with empty entries in the "aud" claim.
+	nonEmptyAudience bool
+
+	// If not nil, all entries of the "aud" claim must be one of these values.
+	exclusiveAudience []string
+
+	// Ignore trailing slashes when comparing audiences.
+	normalizeAudienceURLs bool
+
+	// Reject tokens without a non-empty string "jti" claim.
+	requireJTI bool
+
+	// If verifyNonce is set, the "nonce" claim must equal nonce.
+	nonce       string
+	verifyNonce bool
+
+	// If verifyClientID is set, the "client_id" claim must equal clientID.
+	clientID       string
+	verifyClientID bool
+
+	// Trim leading and trailing whitespace from the token string.
+	trimWhitespace bool
+
+	// Reject tokens whose header or claims are not valid UTF-8.
+	strictUTF8 bool
+
+	// Reject tokens using the "none" signing method.
+	rejectNone bool
+
+	// Reject tokens without a "kid" header.
+	requireKeyID bool
+
+	// Reject keys returned by the Keyfunc that do not fit the signing method of the token.
+	strictKeyType bool
+
+	// Reject tokens with a "jku" or "x5u" header.
+	rejectKeyURLs bool
+
+	// If populated, the "typ" header must match this value.
+	expectedType string
+
+	// If populated, the duration of the parsing steps is recorded here.
+	timings *ParseTimings
+
+	// Decode segments using unpadded, case-insensitive base32 instead of base64url.
+	base32 bool
+
+	// If positive, the claims must not have more top-level keys than this.
+	maxClaims int
+
+	// Reject claims in which a top-level key occurs more than once.
+	rejectDuplicateClaims bool
+
+	// If non-zero, the "iat" claim must not be older than this.
+	maxTokenAge time.Duration
+
+	// If non-zero, the "iat" claim must not be before this.
+	minIssuedAt time.Time
+
+	// If non-zero, the "exp" claim must not be further in the future than this.
+	maxExpiry time.Duration
+
+	// Reject tokens with neither an "exp" nor an "nbf" claim.
+	requireTimeConstraint bool
+
+	// Reject tokens without an "exp" claim.
+	requireExpiration bool
+
+	// Custom time claims, which must not be older than their maximum age.
+	timeClaims []timeClaim
+
+	// If populated, this function is called with the outcome of each claim check.
+	observer func(check string, ok bool, err error)
+
+	// If populated, this function may modify the decoded claims before they are validated.
+	preprocessor func(Claims) error
+
+	// Recover panics of the Keyfunc and return them as error.
+	recoverKeyfunc bool
+
+	// Reject claims that are not marshalled back to the received JSON object.
+	canonicalClaims bool
+}
+
+// ParseTimings holds the time spent in the individual steps of parsing a token, see WithTimings.
+// Steps that were not reached, e.g. because decoding failed, keep their previous value.
+type ParseTimings struct {
+	Decode   time.Duration // Decoding the header and claims
+	Verify   time.Duration // Verifying the signature
+	Validate time.Duration // Validating the claims
+}
+
+// timeClaim is a custom numeric date claim with a maximum age, see WithTimeClaim.
+type timeClaim struct {
+	name   string
+	maxAge time.Duration
 }
 
 // NewParser creates a new Parser with the specified options
@@ -43,11 +154,131 @@ func (p *Parser) Parse(tokenString string, keyFunc Keyfunc) (*Token, error) {
 }
 
 func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyfunc) (*Token, error) {
+	start := p.startTiming()
 	token, parts, err := p.ParseUnverified(tokenString, claims)
+	p.stopTiming(start, func(t *ParseTimings, d time.Duration) { t.Decode = d })
+	if err != nil {
+		return token, err
+	}
+
+	if err = p.checkMethod(token); err != nil {
+		return token, err
+	}
+
+	if err = p.checkHeader(token); err != nil {
+		return token, err
+	}
+
+	// Only the "none" signing method is expected to come without a signature
+	if parts[2] == "" && token.Method.Alg() != SigningMethodNone.Alg() {
+		return token, NewValidationError("token signature is missing", ValidationErrorSignatureInvalid)
+	}
+
+	// Lookup key
+	key, err := p.resolveKey(keyFunc, token)
 	if err != nil {
 		return token, err
 	}
 
+	// Perform validation
+	token.Signature = parts[2]
+	start = p.startTiming()
+	sigErr := p.verifySignature(token, token.SigningInput, key)
+	p.stopTiming(start, func(t *ParseTimings, d time.Duration) { t.Verify = d })
+
+	// Compare the claims to the received JSON only once the signature is verified, since this is
+	// more expensive than decoding them
+	if sigErr == nil && p.canonicalClaims && !claimsMatchJSON(token.Claims, token.RawClaims) {
+		return token, &ValidationError{Inner: ErrTokenClaimsNotCanonical, Errors: ValidationErrorMalformed}
+	}
+
+	// Normalize the claims of e.g. legacy issuers, before they are validated. Claims are only
+	// passed to the preprocessor once the signature is verified.
+	if sigErr == nil && p.preprocessor != nil {
+		if err = p.preprocessor(token.Claims); err != nil {
+			return token, &ValidationError{Inner: err, Errors: ValidationErrorClaimsInvalid}
+		}
+	}
+
+	vErr := &ValidationError{}
+
+	// Validate Claims
+	if !p.SkipClaimsValidation {
+		start = p.startTiming()
+		vErr = p.validateClaims(token.Claims)
+		p.stopTiming(start, func(t *ParseTimings, d time.Duration) { t.Validate = d })
+	}
+
+	if sigErr != nil {
+		vErr.Inner = sigErr
+		vErr.Errors |= ValidationErrorSignatureInvalid
+	}
+
+	if vErr.valid() {
+		token.Valid = true
+		return token, nil
+	}
+
+	return token, vErr
+}
+
+// resolveKey looks up the key for verifying the signature of token using keyFunc, and checks that
+// it may be used.
+func (p *Parser) resolveKey(keyFunc Keyfunc, token *Token) (interface{}, error) {
+	if keyFunc == nil {
+		// keyFunc was not provided.  short circuiting validation
+		return nil, NewValidationError("no Keyfunc was provided.", ValidationErrorUnverifiable)
+	}
+	key, err := p.lookupKey(keyFunc, token)
+	if err != nil {
+		// keyFunc returned an error
+		if ve, ok := err.(*ValidationError); ok {
+			return nil, ve
+		}
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorUnverifiable}
+	}
+	if isNilKey(key) {
+		// keyFunc returned neither a key nor an error
+		return nil, &ValidationError{Inner: ErrKeyfuncNoKey, Errors: ValidationErrorUnverifiable}
+	}
+	if p.strictKeyType && !keyMatchesMethod(token.Method, key) {
+		return nil, &ValidationError{Inner: ErrInvalidKeyType, Errors: ValidationErrorUnverifiable}
+	}
+	if _, ok := key.(unsafeNoneMagicConstant); ok && token.Method == SigningMethodNone && !p.SkipClaimsValidation {
+		// Unsigned tokens are only meant for tests, so their claims must not be trusted as if they were validated
+		return nil, &ValidationError{Inner: ErrNoneClaimsValidation, Errors: ValidationErrorUnverifiable}
+	}
+
+	return key, nil
+}
+
+// checkHeader checks the header of token against the parser's header policies. It is called
+// before the key is looked up, so that the Keyfunc never sees a refused header.
+func (p *Parser) checkHeader(token *Token) error {
+	// Refuse key URLs in the header, before the Keyfunc might follow them
+	if p.rejectKeyURLs {
+		for _, h := range []string{"jku", "x5u"} {
+			if _, ok := token.Header[h]; ok {
+				return &ValidationError{Inner: fmt.Errorf("%w: %s", ErrTokenEmbeddedKeyURL, h), Errors: ValidationErrorUnverifiable}
+			}
+		}
+	}
+
+	// The "typ" header is optional, unless a type is expected
+	if typ, _ := token.Header["typ"].(string); p.expectedType != "" && !strings.EqualFold(typ, p.expectedType) {
+		return &ValidationError{Inner: ErrTokenInvalidType, Errors: ValidationErrorUnverifiable}
+	}
+
+	// Require a key ID before the key is looked up
+	if kid, _ := token.Header["kid"].(string); p.requireKeyID && kid == "" {
+		return &ValidationError{Inner: ErrTokenKeyIDMissing, Errors: ValidationErrorUnverifiable}
+	}
+
+	return nil
+}
+
+// checkMethod checks that the signing method of token is allowed by the parser.
+func (p *Parser) checkMethod(token *Token) error {
 	// Verify signing method is in the required set
 	if p.ValidMethods != nil {
 		var signingMethodValid = false
@@ -60,53 +291,138 @@ func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyf
 		}
 		if !signingMethodValid {
 			// signing method is not in the listed set
-			return token, NewValidationError(fmt.Sprintf("signing method %v is invalid", alg), ValidationErrorSignatureInvalid)
+			return NewValidationError(fmt.Sprintf("signing method %v is invalid", alg), ValidationErrorSignatureInvalid)
 		}
 	}
 
-	// Lookup key
-	var key interface{}
-	if keyFunc == nil {
-		// keyFunc was not provided.  short circuiting validation
-		return token, NewValidationError("no Keyfunc was provided.", ValidationErrorUnverifiable)
+	// Reject the "none" signing method, regardless of the key returned by keyFunc
+	if p.rejectNone && token.Method == SigningMethodNone {
+		return &ValidationError{Inner: NoneSignatureTypeDisallowedError, Errors: ValidationErrorSignatureInvalid}
 	}
-	if key, err = keyFunc(token); err != nil {
-		// keyFunc returned an error
-		if ve, ok := err.(*ValidationError); ok {
-			return token, ve
-		}
-		return token, &ValidationError{Inner: err, Errors: ValidationErrorUnverifiable}
+
+	return nil
+}
+
+// lookupKey calls keyFunc for token. If WithRecoverKeyfunc is set, a panic in keyFunc is recovered
+// and returned as KeyfuncPanicError.
+func (p *Parser) lookupKey(keyFunc Keyfunc, token *Token) (key interface{}, err error) {
+	if p.recoverKeyfunc {
+		defer func() {
+			if r := recover(); r != nil {
+				key, err = nil, &KeyfuncPanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
 	}
 
-	vErr := &ValidationError{}
+	return keyFunc(token)
+}
 
-	// Validate Claims
-	if !p.SkipClaimsValidation {
-		if err := token.Claims.Valid(); err != nil {
-
-			// If the Claims Valid returned an error, check if it is a validation error,
-			// If it was another error type, create a ValidationError with a generic ClaimsInvalid flag set
-			if e, ok := err.(*ValidationError); !ok {
-				vErr = &ValidationError{Inner: err, Errors: ValidationErrorClaimsInvalid}
-			} else {
-				vErr = e
+// startTiming returns the current time, if timings are collected.
+func (p *Parser) startTiming() time.Time {
+	if p.timings == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// stopTiming records the time elapsed since start using set, if timings are collected.
+func (p *Parser) stopTiming(start time.Time, set func(t *ParseTimings, d time.Duration)) {
+	if p.timings != nil {
+		set(p.timings, time.Since(start))
+	}
+}
+
+// verifySignature verifies the signature of token. The signature is decoded here, if the signing
+// method is able to verify decoded signatures, otherwise this is left to the signing method.
+func (p *Parser) verifySignature(token *Token, signingString string, key interface{}) error {
+	bv, ok := token.Method.(bytesVerifier)
+	if !ok {
+		if p.base32 {
+			// Signing methods only understand base64url encoded signatures
+			sig, err := p.decodeSegment(token.Signature)
+			if err != nil {
+				return err
 			}
+			return token.Method.Verify(signingString, EncodeSegment(sig), key)
 		}
+		return token.Method.Verify(signingString, token.Signature, key)
 	}
 
-	// Perform validation
-	token.Signature = parts[2]
-	if err = token.Method.Verify(strings.Join(parts[0:2], "."), token.Signature, key); err != nil {
-		vErr.Inner = err
-		vErr.Errors |= ValidationErrorSignatureInvalid
+	sig, err := p.decodeSegment(token.Signature)
+	if err != nil {
+		return err
 	}
 
-	if vErr.valid() {
-		token.Valid = true
-		return token, nil
+	return bv.VerifyBytes(signingString, sig, key)
+}
+
+// decodeSegment decodes a segment of a token using the encoding configured for the parser.
+func (p *Parser) decodeSegment(seg string) ([]byte, error) {
+	if p.base32 {
+		return base32Encoding.DecodeString(strings.ToUpper(seg))
 	}
 
-	return token, vErr
+	return DecodeSegment(seg)
+}
+
+// keyMatchesMethod reports whether key is suitable for verifying tokens signed using method. Keys
+// for signing methods not implemented by this package are always considered suitable.
+func keyMatchesMethod(method SigningMethod, key interface{}) bool {
+	switch m := method.(type) {
+	case *SigningMethodHMAC:
+		k, ok := m.keyBytes(key)
+		return ok && len(k) > 0
+	case *SigningMethodRSA, *SigningMethodRSAPSS:
+		k, ok := key.(*rsa.PublicKey)
+		return ok && k.N != nil
+	case *SigningMethodECDSA:
+		k, ok := key.(*ecdsa.PublicKey)
+		return ok && k.Curve != nil && k.Curve.Params().BitSize == m.CurveBits
+	case *SigningMethodEd25519:
+		k, ok := key.(ed25519.PublicKey)
+		return ok && len(k) == ed25519.PublicKeySize
+	case *signingMethodNone:
+		_, ok := key.(unsafeNoneMagicConstant)
+		return ok
+	}
+
+	return true
+}
+
+// isNilKey reports whether key is nil, including typed nil values such as a nil *rsa.PublicKey, or
+// an empty HMAC secret, which would make signatures trivial to forge.
+func isNilKey(key interface{}) bool {
+	switch k := key.(type) {
+	case nil:
+		return true
+	case []byte:
+		return len(k) == 0
+	case *HMACKey:
+		return k == nil || len(k.key) == 0
+	}
+
+	switch v := reflect.ValueOf(key); v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// ParseJSON parses a token in the flattened JWS JSON serialization, as produced by Token.SignedJSON.
+// The protected header, payload and signature are re-assembled into the compact serialization, which
+// is then parsed, validated and verified like in Parse. Token.Raw therefore holds the compact form.
+func (p *Parser) ParseJSON(data []byte, keyFunc Keyfunc) (*Token, error) {
+	return p.ParseJSONWithClaims(data, MapClaims{}, keyFunc)
+}
+
+func (p *Parser) ParseJSONWithClaims(data []byte, claims Claims, keyFunc Keyfunc) (*Token, error) {
+	var jws flattenedJSON
+	if err := json.Unmarshal(data, &jws); err != nil {
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+
+	return p.ParseWithClaims(strings.Join([]string{jws.Protected, jws.Payload, jws.Signature}, "."), claims, keyFunc)
 }
 
 // ParseUnverified parses the token but doesn't validate the signature.
@@ -116,55 +432,296 @@ func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyf
 // It's only ever useful in cases where you know the signature is valid (because it has
 // been checked previously in the stack) and you want to extract values from it.
 func (p *Parser) ParseUnverified(tokenString string, claims Claims) (token *Token, parts []string, err error) {
+	if p.trimWhitespace {
+		tokenString = strings.Trim(tokenString, " \t\r\n")
+	}
+
 	parts = strings.Split(tokenString, ".")
 	if len(parts) != 3 {
 		return nil, parts, NewValidationError("token contains an invalid number of segments", ValidationErrorMalformed)
 	}
 
-	token = &Token{Raw: tokenString}
+	token = &Token{Raw: tokenString, SigningInput: strings.Join(parts[0:2], ".")}
+	for _, part := range parts {
+		token.UsedPadding = token.UsedPadding || strings.HasSuffix(part, "=")
+	}
+	if p.base32 {
+		// base32 is case-insensitive, so the signature covers the canonical upper case form
+		token.SigningInput = strings.ToUpper(token.SigningInput)
+		token.base32 = true
+	}
 
 	// parse Header
-	var headerBytes []byte
-	if headerBytes, err = DecodeSegment(parts[0]); err != nil {
+	if err = p.decodeHeader(token, parts[0]); err != nil {
+		// A token with a "bearer " prefix never has a valid encoded header
 		if strings.HasPrefix(strings.ToLower(tokenString), "bearer ") {
 			return token, parts, NewValidationError("tokenstring should not contain 'bearer '", ValidationErrorMalformed)
 		}
-		return token, parts, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
-	}
-	if err = json.Unmarshal(headerBytes, &token.Header); err != nil {
-		return token, parts, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+		return token, parts, err
 	}
 
 	// parse Claims
 	var claimBytes []byte
 	token.Claims = claims
 
-	if claimBytes, err = DecodeSegment(parts[1]); err != nil {
+	unencoded, err := unencodedPayload(token.Header)
+	if err != nil {
 		return token, parts, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
 	}
+
+	if unencoded {
+		claimBytes = []byte(parts[1])
+	} else if claimBytes, err = p.decodeSegment(parts[1]); err != nil {
+		return token, parts, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	token.RawClaims = claimBytes
+	if p.strictUTF8 && !utf8.Valid(claimBytes) {
+		return token, parts, NewValidationError("token claims are not valid UTF-8", ValidationErrorMalformed)
+	}
+	// Reject e.g. compressed or otherwise binary payloads explicitly, rather than failing somewhere
+	// while decoding them, or even ignoring data after the first JSON value
+	if !json.Valid(claimBytes) {
+		return token, parts, &ValidationError{Inner: ErrTokenPayloadNotJSON, Errors: ValidationErrorMalformed}
+	}
+	if p.maxClaims > 0 && !verifyMaxClaims(claimBytes, p.maxClaims) {
+		return token, parts, &ValidationError{Inner: ErrTokenTooManyClaims, Errors: ValidationErrorMalformed}
+	}
+	if p.rejectDuplicateClaims {
+		if name, ok := duplicateClaim(claimBytes); ok {
+			return token, parts, &ValidationError{Inner: fmt.Errorf("%w: %s", ErrTokenDuplicateClaim, name), Errors: ValidationErrorMalformed}
+		}
+	}
 	dec := json.NewDecoder(bytes.NewBuffer(claimBytes))
 	if p.UseJSONNumber {
 		dec.UseNumber()
 	}
 	// JSON Decode.  Special case for map type to avoid weird pointer behavior
 	if c, ok := token.Claims.(MapClaims); ok {
+		// Decoding e.g. null into a map would succeed, so check for an object first
+		if !isJSONObject(claimBytes) {
+			return token, parts, NewValidationError("token claims are not a JSON object", ValidationErrorMalformed)
+		}
 		err = dec.Decode(&c)
 	} else {
 		err = dec.Decode(&claims)
 	}
 	// Handle decode error
 	if err != nil {
-		return token, parts, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+		return token, parts, &ValidationError{Inner: fmt.Errorf("could not decode token claims: %w", err), Errors: ValidationErrorMalformed}
 	}
-
 	// Lookup signature method
-	if method, ok := token.Header["alg"].(string); ok {
-		if token.Method = GetSigningMethod(method); token.Method == nil {
-			return token, parts, NewValidationError("signing method (alg) is unavailable.", ValidationErrorUnverifiable)
-		}
-	} else {
-		return token, parts, NewValidationError("signing method (alg) is unspecified.", ValidationErrorUnverifiable)
+	if err = lookupMethod(token); err != nil {
+		return token, parts, err
 	}
 
 	return token, parts, nil
 }
+
+// decodeHeader decodes the encoded header seg into the header of token.
+func (p *Parser) decodeHeader(token *Token, seg string) error {
+	headerBytes, err := p.decodeSegment(seg)
+	if err != nil {
+		return &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	if p.strictUTF8 && !utf8.Valid(headerBytes) {
+		return NewValidationError("token header is not valid UTF-8", ValidationErrorMalformed)
+	}
+	// Decoding e.g. null into the header would succeed, and arrays fail with a confusing type error
+	if !isJSONObject(headerBytes) {
+		return NewValidationError("token header is not a JSON object", ValidationErrorMalformed)
+	}
+	if err = json.Unmarshal(headerBytes, &token.Header); err != nil {
+		return &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+
+	return nil
+}
+
+// lookupMethod sets the signing method of token according to its "alg" header.
+func lookupMethod(token *Token) error {
+	method, ok := token.Header["alg"].(string)
+	if !ok {
+		return NewValidationError("signing method (alg) is unspecified.", ValidationErrorUnverifiable)
+	}
+	if token.Method = GetSigningMethod(method); token.Method == nil {
+		return NewValidationError("signing method (alg) is unavailable.", ValidationErrorUnverifiable)
+	}
+
+	return nil
+}
+
+// verifyMaxClaims reports whether the JSON object in data has at most max top-level keys. The
+// object is read using a streaming decoder, which stops as soon as the limit is exceeded. Data
+// that is not a JSON object is accepted here and rejected when decoding the claims.
+func verifyMaxClaims(data []byte, max int) bool {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return true
+	}
+
+	for n := 1; dec.More(); n++ {
+		// Read the key
+		if _, err := dec.Token(); err != nil {
+			return true
+		}
+		if n > max {
+			return false
+		}
+
+		if err := skipJSONValue(dec); err != nil {
+			return true
+		}
+	}
+
+	return true
+}
+
+// duplicateClaim returns the first top-level key, which occurs more than once in the JSON object
+// in data. Like verifyMaxClaims, data that is not a JSON object is accepted here.
+func duplicateClaim(data []byte) (name string, ok bool) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return "", false
+	}
+
+	seen := make(map[string]bool)
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return "", false
+		}
+		key, _ := t.(string)
+		if seen[key] {
+			return key, true
+		}
+		seen[key] = true
+
+		if err := skipJSONValue(dec); err != nil {
+			return "", false
+		}
+	}
+
+	return "", false
+}
+
+// skipJSONValue reads the next JSON value from dec, including nested objects and arrays,
+// without decoding it.
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// DecodeClaims decodes the claims of tokenString into a MapClaims, e.g. for debugging or tooling.
+//
+// WARNING: The signature is neither verified nor are the claims validated, so the returned claims
+// must not be trusted.
+func DecodeClaims(tokenString string) (MapClaims, error) {
+	parts := strings.Split(tokenString, ".")
+	if len(parts) != 3 {
+		return nil, NewValidationError("token contains an invalid number of segments", ValidationErrorMalformed)
+	}
+
+	claimBytes, err := DecodeSegment(parts[1])
+	if err != nil {
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	if !isJSONObject(claimBytes) {
+		return nil, NewValidationError("token claims are not a JSON object", ValidationErrorMalformed)
+	}
+
+	claims := MapClaims{}
+	if err = json.Unmarshal(claimBytes, &claims); err != nil {
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+
+	return claims, nil
+}
+
+// PeekHeader decodes only the header of tokenString, e.g. to cheaply reject tokens with a
+// disallowed alg before the claims are decoded and the signature is verified.
+//
+// WARNING: The signature is not verified, so the returned header must not be trusted.
+func PeekHeader(tokenString string) (map[string]interface{}, error) {
+	if strings.Count(tokenString, ".") != 2 {
+		return nil, NewValidationError("token contains an invalid number of segments", ValidationErrorMalformed)
+	}
+
+	headerBytes, err := DecodeSegment(tokenString[:strings.IndexByte(tokenString, '.')])
+	if err != nil {
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	if !isJSONObject(headerBytes) {
+		return nil, NewValidationError("token header is not a JSON object", ValidationErrorMalformed)
+	}
+
+	var header map[string]interface{}
+	if err = json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+
+	return header, nil
+}
+
+// IsWellFormed reports whether tokenString is structurally a JWT in compact serialization: it must
+// consist of three base64url encoded segments, of which the first two contain JSON objects. The
+// signature is not verified and the claims are not validated, so this is only suited to tell
+// JWTs apart from other (e.g. opaque) tokens.
+func IsWellFormed(tokenString string) bool {
+	if strings.Count(tokenString, ".") != 2 {
+		return false
+	}
+
+	i := strings.IndexByte(tokenString, '.')
+	j := i + 1 + strings.IndexByte(tokenString[i+1:], '.')
+
+	return isJSONObjectSegment(tokenString[:i]) &&
+		isJSONObjectSegment(tokenString[i+1:j]) &&
+		isBase64URL(tokenString[j+1:])
+}
+
+// isJSONObjectSegment reports whether seg is a base64url encoded JSON object.
+func isJSONObjectSegment(seg string) bool {
+	b, err := DecodeSegment(seg)
+	if err != nil {
+		return false
+	}
+
+	return isJSONObject(b) && json.Valid(b)
+}
+
+// isJSONObject reports whether b starts like a JSON object. It does not check whether b is
+// valid JSON.
+func isJSONObject(b []byte) bool {
+	b = bytes.TrimLeft(b, " \t\r\n")
+	return len(b) > 0 && b[0] == '{'
+}
+
+// isBase64URL reports whether seg only consists of characters of the base64url alphabet.
+func isBase64URL(seg string) bool {
+	for i := 0; i < len(seg); i++ {
+		switch c := seg[i]; {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_':
+		case c == '=' && DecodePaddingAllowed:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
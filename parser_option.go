@@ -1,5 +1,7 @@
 package jwt
 
+import "time"
+
 // ParserOption is used to implement functional-style options that modify the behavior of the parser. To add
 // new options, just create a function (ideally beginning with With or Without) that returns an anonymous function that
 // takes a *Parser type as input and manipulates its configuration accordingly.
@@ -7,6 +9,7 @@ type ParserOption func(*Parser)
 
 // WithValidMethods is an option to supply algorithm methods that the parser will check. Only those methods will be considered valid.
 // It is heavily encouraged to use this option in order to prevent attacks such as https://auth0.com/blog/critical-vulnerabilities-in-json-web-token-libraries/.
+// This also applies to the "none" signing method, which is refused unless "none" is listed, regardless of the key returned by the Keyfunc.
 func WithValidMethods(methods []string) ParserOption {
 	return func(p *Parser) {
 		p.ValidMethods = methods
@@ -27,3 +30,283 @@ func WithoutClaimsValidation() ParserOption {
 		p.SkipClaimsValidation = true
 	}
 }
+
+// WithIssuer is an option to require the "iss" claim to match iss. Tokens without an "iss" claim
+// are rejected.
+func WithIssuer(iss string) ParserOption {
+	return func(p *Parser) {
+		p.issuers = []string{iss}
+	}
+}
+
+// WithIssuers is an option to require the "iss" claim to match one of issuers, e.g. if tokens of
+// several trusted identity providers are accepted. Tokens without an "iss" claim are rejected.
+func WithIssuers(issuers []string) ParserOption {
+	return func(p *Parser) {
+		p.issuers = issuers
+	}
+}
+
+// WithAudience is an option to require the "aud" claim to contain aud. Tokens without an "aud"
+// claim are rejected. Empty entries in the "aud" claim never match, so that a misconfigured empty
+// aud rejects all tokens instead of accepting tokens with an empty audience.
+func WithAudience(aud string) ParserOption {
+	return func(p *Parser) {
+		p.audience = aud
+		p.verifyAudience = true
+	}
+}
+
+// WithNonEmptyAudience is an option to reject tokens whose "aud" claim contains an empty string,
+// which usually indicates a misconfigured issuer.
+func WithNonEmptyAudience() ParserOption {
+	return func(p *Parser) {
+		p.nonEmptyAudience = true
+	}
+}
+
+// WithExclusiveAudience is an option to reject tokens whose "aud" claim contains any value not in
+// allowed, e.g. to prevent tokens that are also meant for other services from being accepted.
+// Tokens without an "aud" claim are not affected; combine with WithAudience to require one.
+func WithExclusiveAudience(allowed []string) ParserOption {
+	return func(p *Parser) {
+		p.exclusiveAudience = append([]string{}, allowed...)
+	}
+}
+
+// WithAudienceURLNormalization is an option to ignore trailing slashes when comparing the "aud"
+// claim against the audiences configured with WithAudience and WithExclusiveAudience, so that e.g.
+// "https://api.example.com/" matches "https://api.example.com". By default, audiences must match
+// exactly.
+func WithAudienceURLNormalization() ParserOption {
+	return func(p *Parser) {
+		p.normalizeAudienceURLs = true
+	}
+}
+
+// WithRequireJTI is an option to require the "jti" claim to be a non-empty string. Tokens without
+// a "jti" claim are rejected with ErrTokenRequiredClaimMissing, tokens with an empty or non-string
+// "jti" claim with ErrTokenInvalidId.
+func WithRequireJTI() ParserOption {
+	return func(p *Parser) {
+		p.requireJTI = true
+	}
+}
+
+// WithNonce is an option to require the "nonce" claim of an OpenID Connect ID token to equal the
+// nonce sent by the client in the authentication request. Tokens without a "nonce" claim are
+// rejected with ErrTokenRequiredClaimMissing, tokens with a nonce that is not a string with
+// ErrInvalidType, and tokens with a different nonce with ErrTokenInvalidNonce.
+func WithNonce(nonce string) ParserOption {
+	return func(p *Parser) {
+		p.nonce = nonce
+		p.verifyNonce = true
+	}
+}
+
+// WithClientID is an option to require the "client_id" claim of an OAuth 2.0 access token (RFC 9068)
+// to equal the identifier of the authenticated client. Tokens without a "client_id" claim are
+// rejected with ErrTokenRequiredClaimMissing, tokens with a "client_id" claim that is not a string
+// with ErrInvalidType, and tokens of a different client with ErrTokenInvalidClientID.
+func WithClientID(clientID string) ParserOption {
+	return func(p *Parser) {
+		p.clientID = clientID
+		p.verifyClientID = true
+	}
+}
+
+// WithTrimWhitespace is an option to remove leading and trailing spaces, tabs and line breaks
+// from the token string before it is parsed. This accommodates clients that send the token with
+// stray whitespace. Characters within the token are never modified.
+func WithTrimWhitespace() ParserOption {
+	return func(p *Parser) {
+		p.trimWhitespace = true
+	}
+}
+
+// WithStrictUTF8 is an option to reject tokens whose decoded header or claims are not valid UTF-8,
+// as required for JSON by RFC 8259. Otherwise, invalid bytes in strings are silently replaced by
+// the Unicode replacement character when decoding.
+func WithStrictUTF8() ParserOption {
+	return func(p *Parser) {
+		p.strictUTF8 = true
+	}
+}
+
+// WithValidationObserver is an option to register a function, which is called with the outcome of
+// each claim check during validation, e.g. for metrics. check is the name of the checked claim,
+// such as "exp" or "aud", and err is the reason for a failed check. The observer does not influence
+// whether validation succeeds. Checks that cannot be attributed to a single claim, e.g. errors
+// returned by a custom Valid method, are reported as "claims".
+func WithValidationObserver(observer func(check string, ok bool, err error)) ParserOption {
+	return func(p *Parser) {
+		p.observer = observer
+	}
+}
+
+// WithRecoverKeyfunc is an option to recover panics of the Keyfunc, e.g. caused by a bug in the
+// key lookup, and fail parsing with a KeyfuncPanicError instead, which matches ErrKeyfuncPanic and
+// ErrTokenUnverifiable. By default, such panics are propagated to the caller.
+func WithRecoverKeyfunc() ParserOption {
+	return func(p *Parser) {
+		p.recoverKeyfunc = true
+	}
+}
+
+// WithClaimsPreprocessor is an option to register a function, which is called with the decoded
+// claims before they are validated, e.g. to convert an "exp" claim in milliseconds of a legacy
+// issuer to seconds. It is only called once the signature is verified, so that it never sees
+// forged claims, and may modify the claims in place. An error returned by preprocess fails parsing
+// with ValidationErrorClaimsInvalid.
+func WithClaimsPreprocessor(preprocess func(Claims) error) ParserOption {
+	return func(p *Parser) {
+		p.preprocessor = preprocess
+	}
+}
+
+// WithMaxTokenAge is an option to reject tokens that were issued more than d ago, according to
+// their "iat" claim, regardless of their "exp" claim. Tokens without an "iat" claim are rejected.
+func WithMaxTokenAge(d time.Duration) ParserOption {
+	return func(p *Parser) {
+		p.maxTokenAge = d
+	}
+}
+
+// WithMinIssuedAt is an option to reject tokens that were issued before t, according to their
+// "iat" claim, regardless of their "exp" claim, e.g. to revoke all tokens issued before a key
+// compromise. Tokens without an "iat" claim are rejected.
+func WithMinIssuedAt(t time.Time) ParserOption {
+	return func(p *Parser) {
+		p.minIssuedAt = t
+	}
+}
+
+// WithMaxExpiry is an option to reject tokens whose "exp" claim is more than d in the future, which
+// indicates an issuer bug or abuse. Tokens without an "exp" claim are not affected.
+func WithMaxExpiry(d time.Duration) ParserOption {
+	return func(p *Parser) {
+		p.maxExpiry = d
+	}
+}
+
+// WithExpirationRequired is an option to reject tokens without an "exp" claim, since such tokens
+// are valid indefinitely. Expired tokens are rejected regardless of this option.
+func WithExpirationRequired() ParserOption {
+	return func(p *Parser) {
+		p.requireExpiration = true
+	}
+}
+
+// WithTimeConstraintRequired is an option to reject tokens that have neither an "exp" nor an "nbf"
+// claim, since such tokens are valid indefinitely.
+func WithTimeConstraintRequired() ParserOption {
+	return func(p *Parser) {
+		p.requireTimeConstraint = true
+	}
+}
+
+// WithStrictKeyTypeCheck is an option to check that the key returned by the Keyfunc fits the
+// signing method of the token before verifying the signature, e.g. a non-empty []byte for HMAC or
+// an *ecdsa.PublicKey on the curve matching the algorithm for ECDSA. Tokens with an unsuitable key
+// are rejected with ErrInvalidKeyType. Keys for custom signing methods are not checked.
+func WithStrictKeyTypeCheck() ParserOption {
+	return func(p *Parser) {
+		p.strictKeyType = true
+	}
+}
+
+// WithRejectNone is an option to reject tokens using the "none" signing method with
+// NoneSignatureTypeDisallowedError, even if the Keyfunc returns UnsafeAllowNoneSignatureType.
+// Like other parsing errors, the returned token still carries the decoded, but untrusted,
+// header and claims, e.g. for logging.
+func WithRejectNone() ParserOption {
+	return func(p *Parser) {
+		p.rejectNone = true
+	}
+}
+
+// WithKeyIDRequired is an option to reject tokens whose header does not contain a non-empty "kid"
+// string. This is checked before the Keyfunc is called.
+func WithKeyIDRequired() ParserOption {
+	return func(p *Parser) {
+		p.requireKeyID = true
+	}
+}
+
+// WithMaxClaims is an option to reject tokens whose claims have more than n top-level keys, to
+// protect against the memory and processing cost of untrusted tokens with excessive claims. The
+// keys are counted using a streaming decoder before the claims are decoded.
+func WithMaxClaims(n int) ParserOption {
+	return func(p *Parser) {
+		p.maxClaims = n
+	}
+}
+
+// WithoutDuplicateClaims is an option to reject tokens in which a top-level claim occurs more than
+// once, e.g. {"aud":"a","aud":["b"]}. Such claims are otherwise decoded deterministically, with
+// the last occurrence taking precedence, but different JSON implementations may disagree on them.
+func WithoutDuplicateClaims() ParserOption {
+	return func(p *Parser) {
+		p.rejectDuplicateClaims = true
+	}
+}
+
+// WithTimeClaim is an option to require the numeric date claim name, e.g. "auth_time", to be no
+// older than maxAge, like WithMaxTokenAge does for "iat". Tokens without the claim are rejected.
+// The option can be given multiple times for different claims.
+func WithTimeClaim(name string, maxAge time.Duration) ParserOption {
+	return func(p *Parser) {
+		p.timeClaims = append(p.timeClaims, timeClaim{name: name, maxAge: maxAge})
+	}
+}
+
+// WithRejectEmbeddedKeyURLs is an option to reject tokens whose header contains a "jku" or "x5u"
+// parameter. Fetching keys from a URL chosen by the token is a known SSRF vector and lets an
+// attacker supply their own key, so such tokens are rejected before the Keyfunc is called.
+func WithRejectEmbeddedKeyURLs() ParserOption {
+	return func(p *Parser) {
+		p.rejectKeyURLs = true
+	}
+}
+
+// WithExpectedType is an option to require the "typ" header to match typ, ignoring case, e.g.
+// "at+jwt" for access tokens as specified in RFC 9068. Tokens without a "typ" header are rejected.
+// Without this option, the "typ" header is not checked and may be omitted.
+func WithExpectedType(typ string) ParserOption {
+	return func(p *Parser) {
+		p.expectedType = typ
+	}
+}
+
+// WithCanonicalClaimsCheck is an option to reject tokens whose claims are not marshalled back to
+// the received JSON object after decoding, with ErrTokenClaimsNotCanonical. The comparison ignores
+// the order of keys, whitespace, the notation of numbers and whether a single "aud" is a string or
+// a list, but detects e.g. claims that are dropped by the claims type, or numbers that lose
+// precision. Use WithoutDuplicateClaims to detect duplicate keys. The check is only performed once
+// the signature is verified, and not by ParseUnverified. This is meant for strict interoperability
+// tests.
+func WithCanonicalClaimsCheck() ParserOption {
+	return func(p *Parser) {
+		p.canonicalClaims = true
+	}
+}
+
+// WithTimings is an option to record the time spent decoding, verifying and validating a token in
+// timings, e.g. for metrics. timings is overwritten by each parse, so a parser using this option
+// must not be used concurrently. Without this option, no time measurements are taken.
+func WithTimings(timings *ParseTimings) ParserOption {
+	return func(p *Parser) {
+		p.timings = timings
+	}
+}
+
+// WithBase32Encoding is an option to decode the segments of a token using unpadded,
+// case-insensitive base32 (RFC 4648) instead of base64url. This is not compliant with RFC 7519
+// and only meant to interoperate with systems that cannot transport mixed-case tokens. The
+// signature is verified over the upper case form of the encoded segments. Tokens are encoded this
+// way using the WithBase32Segments token option.
+func WithBase32Encoding() ParserOption {
+	return func(p *Parser) {
+		p.base32 = true
+	}
+}
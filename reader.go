@@ -0,0 +1,103 @@
+package jwt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+)
+
+// maxLineSize is the maximum size of a single line read by ParseReader.
+const maxLineSize = 1 << 20
+
+var (
+	// ErrLineTooLong is reported by ParseReader for lines longer than 1 MiB.
+	ErrLineTooLong = errors.New("line is too long")
+)
+
+// ParseResult is the result of parsing a single token, see ParseReader.
+type ParseResult struct {
+	Line  int    // The line number or position of the token, starting at 1
+	Token *Token // The parsed token, which might be nil or invalid if Err is set
+	Err   error  // The error returned by Parse, if any
+}
+
+// ParseReader parses newline-delimited tokens read from r, e.g. from a log file, and sends a
+// ParseResult for each non-empty line to the returned channel, which is closed after r is
+// exhausted. Errors are reported per line and do not stop reading. Lines longer than 1 MiB are
+// skipped and reported as malformed, wrapping ErrLineTooLong. If reading from r fails, the last
+// result carries the read error and a nil Token.
+//
+// The caller must either drain the channel or cancel ctx. Once ctx is canceled, no further results
+// are sent, r is not read any further and the channel is closed; a read in progress is not
+// interrupted, though.
+func ParseReader(ctx context.Context, r io.Reader, keyFunc Keyfunc, options ...ParserOption) <-chan ParseResult {
+	return NewParser(options...).ParseReader(ctx, r, keyFunc)
+}
+
+// ParseReader is like the package level ParseReader, using this parser for each token.
+func (p *Parser) ParseReader(ctx context.Context, r io.Reader, keyFunc Keyfunc) <-chan ParseResult {
+	results := make(chan ParseResult)
+
+	go func() {
+		defer close(results)
+
+		send := func(res ParseResult) bool {
+			select {
+			case results <- res:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		br := bufio.NewReader(r)
+		var buf []byte
+		for line := 1; ctx.Err() == nil; line++ {
+			b, tooLong, err := readLine(br, buf[:0])
+			buf = b
+			if err != nil && err != io.EOF {
+				send(ParseResult{Line: line, Err: err})
+				return
+			}
+
+			if tooLong {
+				if !send(ParseResult{Line: line, Err: &ValidationError{Inner: ErrLineTooLong, Errors: ValidationErrorMalformed}}) {
+					return
+				}
+			} else if tokenString := string(bytes.TrimSpace(b)); tokenString != "" {
+				token, parseErr := p.Parse(tokenString, keyFunc)
+				if !send(ParseResult{Line: line, Token: token, Err: parseErr}) {
+					return
+				}
+			}
+
+			if err == io.EOF {
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// readLine appends the next line read from br to buf, including its line ending. Lines longer
+// than maxLineSize are discarded up to the next newline, without buffering them, and reported as
+// too long.
+func readLine(br *bufio.Reader, buf []byte) ([]byte, bool, error) {
+	tooLong := false
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if !tooLong {
+			if len(buf)+len(bytes.TrimRight(chunk, "\r\n")) > maxLineSize {
+				tooLong, buf = true, buf[:0]
+			} else {
+				buf = append(buf, chunk...)
+			}
+		}
+		if err != bufio.ErrBufferFull {
+			return buf, tooLong, err
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestParseReader(t *testing.T) {
+	valid := signToken(jwt.MapClaims{"foo": "bar"}, jwt.SigningMethodRS256)
+	expired := signToken(jwt.MapClaims{"exp": 1}, jwt.SigningMethodRS256)
+
+	log := strings.Join([]string{valid, "", "not a token", expired, "  " + valid + "\r", strings.Repeat("a", 2<<20), valid, valid}, "\n")
+
+	var results []jwt.ParseResult
+	for res := range jwt.ParseReader(context.Background(), strings.NewReader(log), defaultKeyFunc) {
+		results = append(results, res)
+	}
+
+	want := []struct {
+		line int
+		err  error
+	}{
+		{1, nil},
+		{3, jwt.ErrTokenMalformed},
+		{4, jwt.ErrTokenExpired},
+		{5, nil},
+		{6, jwt.ErrLineTooLong},
+		{7, nil},
+		{8, nil},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("ParseReader() returned %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		res := results[i]
+		if res.Line != w.line {
+			t.Errorf("result %d: Line = %d, want %d", i, res.Line, w.line)
+		}
+		if w.err == nil && (res.Err != nil || !res.Token.Valid) {
+			t.Errorf("result %d: unexpected error = %v", i, res.Err)
+		}
+		if w.err != nil && !errors.Is(res.Err, w.err) {
+			t.Errorf("result %d: error = %v, want %v", i, res.Err, w.err)
+		}
+	}
+}
+
+func TestParseReader_ReadError(t *testing.T) {
+	r := iotest.TimeoutReader(strings.NewReader(signToken(jwt.MapClaims{}, jwt.SigningMethodRS256) + "\n"))
+
+	var results []jwt.ParseResult
+	for res := range jwt.ParseReader(context.Background(), r, defaultKeyFunc) {
+		results = append(results, res)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("ParseReader() returned %d results, want 2", len(results))
+	}
+	if results[0].Err != nil {
+		t.Errorf("result 0: unexpected error = %v", results[0].Err)
+	}
+	if !errors.Is(results[1].Err, iotest.ErrTimeout) || results[1].Token != nil {
+		t.Errorf("result 1: error = %v, want %v", results[1].Err, iotest.ErrTimeout)
+	}
+}
+
+func TestParseReader_Cancel(t *testing.T) {
+	log := strings.Repeat(signToken(jwt.MapClaims{}, jwt.SigningMethodRS256)+"\n", 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := jwt.ParseReader(ctx, strings.NewReader(log), defaultKeyFunc)
+
+	// Stop consuming after the first result
+	if res := <-results; res.Err != nil {
+		t.Errorf("result 0: unexpected error = %v", res.Err)
+	}
+	cancel()
+
+	// The channel is closed without the remaining results being consumed
+	n := 0
+	for range results {
+		n++
+	}
+	if n > 1 {
+		t.Errorf("ParseReader() sent %d results after cancellation, want at most 1", n)
+	}
+}
@@ -0,0 +1,23 @@
+package request
+
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// contextKey is the type of the key under which a token is stored in a context, which prevents
+// collisions with keys defined in other packages.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries token, e.g. to pass a token verified by a
+// middleware on to the handlers. Use FromContext to retrieve it.
+func NewContext(ctx context.Context, token *jwt.Token) context.Context {
+	return context.WithValue(ctx, contextKey{}, token)
+}
+
+// FromContext returns the token stored in ctx by NewContext, if any.
+func FromContext(ctx context.Context) (*jwt.Token, bool) {
+	token, ok := ctx.Value(contextKey{}).(*jwt.Token)
+	return token, ok && token != nil
+}
@@ -0,0 +1,35 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestContext(t *testing.T) {
+	if token, ok := FromContext(context.Background()); ok || token != nil {
+		t.Fatalf("FromContext() = %v, %v, want no token", token, ok)
+	}
+	if token, ok := FromContext(NewContext(context.Background(), nil)); ok || token != nil {
+		t.Fatalf("FromContext() = %v, %v, want no token", token, ok)
+	}
+
+	want := &jwt.Token{Claims: jwt.MapClaims{"sub": "foo"}, Valid: true}
+	var got *jwt.Token
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	})
+	middleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), want)))
+		})
+	}
+
+	middleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if got != want {
+		t.Errorf("FromContext() = %v, want %v", got, want)
+	}
+}
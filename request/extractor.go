@@ -3,6 +3,7 @@ package request
 import (
 	"errors"
 	"net/http"
+	"strings"
 )
 
 // Errors
@@ -31,6 +32,25 @@ func (e HeaderExtractor) ExtractToken(req *http.Request) (string, error) {
 	return "", ErrNoTokenInRequest
 }
 
+// PrefixedHeaderExtractor is an extractor for finding a token in the header Name, e.g.
+// "X-Auth-Token". If StripPrefix is set, e.g. to "Bearer ", it is removed from the beginning of the
+// header value, ignoring case. A value without the prefix is returned as is.
+type PrefixedHeaderExtractor struct {
+	Name        string
+	StripPrefix string
+}
+
+func (e PrefixedHeaderExtractor) ExtractToken(req *http.Request) (string, error) {
+	tok := req.Header.Get(e.Name)
+	if n := len(e.StripPrefix); n > 0 && len(tok) >= n && strings.EqualFold(tok[:n], e.StripPrefix) {
+		tok = tok[n:]
+	}
+	if tok == "" {
+		return "", ErrNoTokenInRequest
+	}
+	return tok, nil
+}
+
 // ArgumentExtractor extracts a token from request arguments.  This includes a POSTed form or
 // GET URL arguments.  Argument names are tried in order until there's a match.
 // This extractor calls `ParseMultipartForm` on the request
@@ -65,6 +85,27 @@ func (e MultiExtractor) ExtractToken(req *http.Request) (string, error) {
 	return "", ErrNoTokenInRequest
 }
 
+// ExtractTokens extracts a value from req using extractor and splits it at commas, e.g. for clients
+// sending several tokens in a single header. Whitespace around the tokens is removed and empty
+// entries are skipped. If no token remains, ErrNoTokenInRequest is returned.
+func ExtractTokens(req *http.Request, extractor Extractor) ([]string, error) {
+	value, err := extractor.ExtractToken(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []string
+	for _, tok := range strings.Split(value, ",") {
+		if tok = strings.TrimSpace(tok); tok != "" {
+			tokens = append(tokens, tok)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, ErrNoTokenInRequest
+	}
+	return tokens, nil
+}
+
 // PostExtractionFilter wraps an Extractor in this to post-process the value before it's handed off.
 // See AuthorizationHeaderExtractor for an example
 type PostExtractionFilter struct {
@@ -1,9 +1,11 @@
 package request
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -53,6 +55,49 @@ var extractorTestData = []struct {
 		token:     "",
 		err:       ErrNoTokenInRequest,
 	},
+	{
+		name:      "prefixed header",
+		extractor: PrefixedHeaderExtractor{Name: "X-Auth-Token"},
+		headers:   map[string]string{"X-Auth-Token": extractorTestTokenA},
+		query:     nil,
+		token:     extractorTestTokenA,
+		err:       nil,
+	},
+	{
+		name:      "prefixed header with prefix",
+		extractor: PrefixedHeaderExtractor{Name: "X-Auth-Token", StripPrefix: "Token "},
+		headers:   map[string]string{"X-Auth-Token": "token " + extractorTestTokenA},
+		query:     nil,
+		token:     extractorTestTokenA,
+		err:       nil,
+	},
+	{
+		name:      "prefixed header without prefix",
+		extractor: PrefixedHeaderExtractor{Name: "X-Auth-Token", StripPrefix: "Token "},
+		headers:   map[string]string{"X-Auth-Token": extractorTestTokenA},
+		query:     nil,
+		token:     extractorTestTokenA,
+		err:       nil,
+	},
+	{
+		name:      "prefixed header with only prefix",
+		extractor: PrefixedHeaderExtractor{Name: "X-Auth-Token", StripPrefix: "Token "},
+		headers:   map[string]string{"X-Auth-Token": "Token "},
+		query:     nil,
+		token:     "",
+		err:       ErrNoTokenInRequest,
+	},
+	{
+		name: "prefixed header miss",
+		extractor: MultiExtractor{
+			PrefixedHeaderExtractor{Name: "X-Auth-Token"},
+			ArgumentExtractor{"token"},
+		},
+		headers: map[string]string{"Foo": extractorTestTokenA},
+		query:   url.Values{"token": {extractorTestTokenB}},
+		token:   extractorTestTokenB,
+		err:     nil,
+	},
 	{
 		name:      "filter",
 		extractor: AuthorizationHeaderExtractor,
@@ -82,6 +127,67 @@ func TestExtractor(t *testing.T) {
 	}
 }
 
+func TestAuthorizationHeaderExtractor_MultipleValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		token  string
+		err    error
+	}{
+		{"bearer after other scheme", []string{"Basic Zm9vOmJhcg==", "Bearer " + extractorTestTokenA}, extractorTestTokenA, nil},
+		{"first bearer wins", []string{"bearer " + extractorTestTokenA, "Bearer " + extractorTestTokenB}, extractorTestTokenA, nil},
+		{"empty value skipped", []string{"", extractorTestTokenA}, extractorTestTokenA, nil},
+		{"no bearer among several", []string{"Basic Zm9vOmJhcg==", "Digest foo"}, "", ErrNoTokenInRequest},
+		{"no values", nil, "", ErrNoTokenInRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			for _, v := range tc.values {
+				r.Header.Add("Authorization", v)
+			}
+
+			token, err := AuthorizationHeaderExtractor.ExtractToken(r)
+			if token != tc.token {
+				t.Errorf("Expected token '%v'.  Got '%v'", tc.token, token)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Expected error '%v'.  Got '%v'", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestExtractTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		tokens []string
+		err    error
+	}{
+		{"single token", "Bearer " + extractorTestTokenA, []string{extractorTestTokenA}, nil},
+		{"two tokens", "Bearer " + extractorTestTokenA + ", " + extractorTestTokenB, []string{extractorTestTokenA, extractorTestTokenB}, nil},
+		{"empty entries skipped", "Bearer ," + extractorTestTokenA + ",,", []string{extractorTestTokenA}, nil},
+		{"only commas", "Bearer , ,", nil, ErrNoTokenInRequest},
+		{"no header", "", nil, ErrNoTokenInRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := makeExampleRequest("GET", "/", map[string]string{"Authorization": tc.header}, nil)
+
+			tokens, err := ExtractTokens(r, AuthorizationHeaderExtractor)
+			if !reflect.DeepEqual(tokens, tc.tokens) {
+				t.Errorf("Expected tokens %v.  Got %v", tc.tokens, tokens)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Expected error '%v'.  Got '%v'", tc.err, err)
+			}
+		})
+	}
+}
+
 func makeExampleRequest(method, path string, headers map[string]string, urlArgs url.Values) *http.Request {
 	r, _ := http.NewRequest(method, fmt.Sprintf("%v?%v", path, urlArgs.Encode()), nil)
 	for k, v := range headers {
@@ -1,22 +1,58 @@
 package request
 
 import (
+	"fmt"
+	"net/http"
 	"strings"
 )
 
 // Strips 'Bearer ' prefix from bearer token string
 func stripBearerPrefixFromTokenString(tok string) (string, error) {
 	// Should be a bearer token
-	if len(tok) > 6 && strings.ToUpper(tok[0:7]) == "BEARER " {
+	if hasBearerPrefix(tok) {
 		return tok[7:], nil
 	}
 	return tok, nil
 }
 
+func hasBearerPrefix(tok string) bool {
+	return len(tok) > 6 && strings.ToUpper(tok[0:7]) == "BEARER "
+}
+
+// authorizationHeaderValuesExtractor extracts the Authorization header, which some proxies
+// duplicate. The first bearer token among all values is returned. A single value without the
+// bearer prefix is returned as is.
+type authorizationHeaderValuesExtractor struct{}
+
+func (authorizationHeaderValuesExtractor) ExtractToken(req *http.Request) (string, error) {
+	var values []string
+	for _, v := range req.Header.Values("Authorization") {
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+
+	for _, v := range values {
+		if hasBearerPrefix(v) {
+			return v, nil
+		}
+	}
+
+	switch len(values) {
+	case 0:
+		return "", ErrNoTokenInRequest
+	case 1:
+		return values[0], nil
+	default:
+		return "", fmt.Errorf("%w: none of %d Authorization headers contains a bearer token", ErrNoTokenInRequest, len(values))
+	}
+}
+
 // AuthorizationHeaderExtractor extracts a bearer token from Authorization header
-// Uses PostExtractionFilter to strip "Bearer " prefix from header
+// Uses PostExtractionFilter to strip "Bearer " prefix from header.
+// If the header is present multiple times, the first bearer token is used.
 var AuthorizationHeaderExtractor = &PostExtractionFilter{
-	HeaderExtractor{"Authorization"},
+	authorizationHeaderValuesExtractor{},
 	stripBearerPrefixFromTokenString,
 }
 
@@ -0,0 +1,96 @@
+package request
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Redacted replaces tokens in requests redacted using TokenLocation.Redact.
+const Redacted = "REDACTED"
+
+// TokenLocation describes where in a request a token was found, so that it can be redacted, e.g.
+// before logging the request.
+type TokenLocation struct {
+	Header   string // The name of the header containing the token, if any
+	Argument string // The name of the query or form argument containing the token, if any
+
+	token string
+}
+
+// ExtractTokenLocation extracts a token from req using extractor, like extractor.ExtractToken,
+// and additionally reports where the token was found. Extractors that transform the token, e.g.
+// by decoding it, are not supported: if the token cannot be found verbatim in a header or
+// argument of req, the returned location is nil.
+func ExtractTokenLocation(req *http.Request, extractor Extractor) (string, *TokenLocation, error) {
+	tok, err := extractor.ExtractToken(req)
+	if err != nil {
+		return "", nil, err
+	}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if containsToken(req.Header[name], tok) {
+			return tok, &TokenLocation{Header: name, token: tok}, nil
+		}
+	}
+
+	for _, args := range []url.Values{req.URL.Query(), req.Form} {
+		names = names[:0]
+		for name := range args {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if containsToken(args[name], tok) {
+				return tok, &TokenLocation{Argument: name, token: tok}, nil
+			}
+		}
+	}
+
+	return tok, nil, nil
+}
+
+// Redact returns a copy of req, in which the token is replaced by Redacted. The original
+// request is not modified. The body of the request is not copied and therefore not redacted,
+// but the parsed form values are.
+func (l *TokenLocation) Redact(req *http.Request) *http.Request {
+	r := req.Clone(req.Context())
+
+	if l.Header != "" {
+		redactValues(r.Header[l.Header], l.token)
+	}
+
+	if l.Argument != "" {
+		if q := r.URL.Query(); containsToken(q[l.Argument], l.token) {
+			redactValues(q[l.Argument], l.token)
+			r.URL.RawQuery = q.Encode()
+		}
+		redactValues(r.Form[l.Argument], l.token)
+		redactValues(r.PostForm[l.Argument], l.token)
+	}
+
+	return r
+}
+
+// containsToken reports whether any of values contains tok.
+func containsToken(values []string, tok string) bool {
+	for _, v := range values {
+		if strings.Contains(v, tok) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValues replaces tok in values by Redacted.
+func redactValues(values []string, tok string) {
+	for i, v := range values {
+		values[i] = strings.Replace(v, tok, Redacted, -1)
+	}
+}
@@ -0,0 +1,72 @@
+package request
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestTokenLocation_Redact(t *testing.T) {
+	tests := []struct {
+		name      string
+		extractor Extractor
+		headers   map[string]string
+		query     url.Values
+		location  TokenLocation
+		header    string
+		rawQuery  string
+	}{
+		{
+			name:      "authorization header",
+			extractor: OAuth2Extractor,
+			headers:   map[string]string{"Authorization": "Bearer " + extractorTestTokenA, "Foo": "bar"},
+			location:  TokenLocation{Header: "Authorization"},
+			header:    "Bearer " + Redacted,
+			rawQuery:  "",
+		},
+		{
+			name:      "query argument",
+			extractor: OAuth2Extractor,
+			headers:   map[string]string{"Foo": "bar"},
+			query:     url.Values{"access_token": {extractorTestTokenA}, "page": {"2"}},
+			location:  TokenLocation{Argument: "access_token"},
+			rawQuery:  "access_token=" + Redacted + "&page=2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := makeExampleRequest("GET", "/", tc.headers, tc.query)
+			rawQuery := r.URL.RawQuery
+
+			tok, loc, err := ExtractTokenLocation(r, tc.extractor)
+			if err != nil || tok != extractorTestTokenA {
+				t.Fatalf("ExtractTokenLocation() = %q, %v", tok, err)
+			}
+			if loc == nil || loc.Header != tc.location.Header || loc.Argument != tc.location.Argument {
+				t.Fatalf("ExtractTokenLocation() location = %+v, want %+v", loc, tc.location)
+			}
+
+			redacted := loc.Redact(r)
+			if tc.header != "" && redacted.Header.Get(tc.location.Header) != tc.header {
+				t.Errorf("Redact() header = %q, want %q", redacted.Header.Get(tc.location.Header), tc.header)
+			}
+			if redacted.URL.RawQuery != tc.rawQuery || redacted.Header.Get("Foo") != "bar" {
+				t.Errorf("Redact() query = %q, want %q", redacted.URL.RawQuery, tc.rawQuery)
+			}
+			if r.URL.RawQuery != rawQuery || r.Header.Get("Authorization") != tc.headers["Authorization"] {
+				t.Errorf("Redact() modified the original request")
+			}
+		})
+	}
+
+	// Transformed tokens cannot be located
+	r := makeExampleRequest("GET", "/", map[string]string{"Foo": extractorTestTokenA}, nil)
+	extractor := &PostExtractionFilter{HeaderExtractor{"Foo"}, func(s string) (string, error) { return s + s, nil }}
+	if _, loc, err := ExtractTokenLocation(r, extractor); err != nil || loc != nil {
+		t.Errorf("ExtractTokenLocation() location = %+v, %v, want nil", loc, err)
+	}
+
+	if _, _, err := ExtractTokenLocation(r, HeaderExtractor{"Bar"}); err != ErrNoTokenInRequest {
+		t.Errorf("ExtractTokenLocation() error = %v, want %v", err, ErrNoTokenInRequest)
+	}
+}
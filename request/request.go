@@ -2,6 +2,7 @@ package request
 
 import (
 	"net/http"
+	"reflect"
 
 	"github.com/golang-jwt/jwt/v4"
 )
@@ -13,8 +14,46 @@ import (
 //
 // You can provide options to modify parsing behavior
 func ParseFromRequest(req *http.Request, extractor Extractor, keyFunc jwt.Keyfunc, options ...ParseFromRequestOption) (token *jwt.Token, err error) {
+	p := newFromRequestParser(req, extractor, options)
+
+	// perform extract
+	tokenString, err := p.extractor.ExtractToken(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// perform parse
+	return p.parser.ParseWithClaims(tokenString, p.claims, keyFunc)
+}
+
+// ParseAllFromRequest extracts several comma-separated tokens from an HTTP request using
+// ExtractTokens and parses each of them like ParseFromRequest. The results are in the order of the
+// tokens, with ParseResult.Line set to the position of the token, starting at 1. Errors are
+// reported per token; err is only returned if no token could be extracted.
+//
+// Each token is parsed into new claims of the same type as the claims passed using WithClaims, so
+// these should be a pointer to a struct or a MapClaims.
+func ParseAllFromRequest(req *http.Request, extractor Extractor, keyFunc jwt.Keyfunc, options ...ParseFromRequestOption) ([]jwt.ParseResult, error) {
+	p := newFromRequestParser(req, extractor, options)
+
+	tokenStrings, err := ExtractTokens(req, p.extractor)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]jwt.ParseResult, len(tokenStrings))
+	for i, tokenString := range tokenStrings {
+		token, err := p.parser.ParseWithClaims(tokenString, newClaims(p.claims), keyFunc)
+		results[i] = jwt.ParseResult{Line: i + 1, Token: token, Err: err}
+	}
+
+	return results, nil
+}
+
+// newFromRequestParser creates a fromRequestParser, applying options and defaults.
+func newFromRequestParser(req *http.Request, extractor Extractor, options []ParseFromRequestOption) *fromRequestParser {
 	// Create basic parser struct
-	p := &fromRequestParser{req, extractor, nil, nil}
+	p := &fromRequestParser{req: req, extractor: extractor}
 
 	// Handle options
 	for _, option := range options {
@@ -28,15 +67,28 @@ func ParseFromRequest(req *http.Request, extractor Extractor, keyFunc jwt.Keyfun
 	if p.parser == nil {
 		p.parser = &jwt.Parser{}
 	}
-
-	// perform extract
-	tokenString, err := p.extractor.ExtractToken(req)
-	if err != nil {
-		return nil, err
+	if p.validMethods != nil {
+		// Copy the parser, which might have been passed using WithParser, instead of modifying it
+		parser := *p.parser
+		jwt.WithValidMethods(p.validMethods)(&parser)
+		p.parser = &parser
 	}
 
-	// perform parse
-	return p.parser.ParseWithClaims(tokenString, p.claims, keyFunc)
+	return p
+}
+
+// newClaims returns new, empty claims of the same type as claims. Claims that are neither a pointer
+// nor a map are returned as is.
+func newClaims(claims jwt.Claims) jwt.Claims {
+	t := reflect.TypeOf(claims)
+	switch t.Kind() {
+	case reflect.Ptr:
+		return reflect.New(t.Elem()).Interface().(jwt.Claims)
+	case reflect.Map:
+		return reflect.MakeMap(t).Interface().(jwt.Claims)
+	default:
+		return claims
+	}
 }
 
 // ParseFromRequestWithClaims is an alias for ParseFromRequest but with custom Claims type.
@@ -47,10 +99,11 @@ func ParseFromRequestWithClaims(req *http.Request, extractor Extractor, claims j
 }
 
 type fromRequestParser struct {
-	req       *http.Request
-	extractor Extractor
-	claims    jwt.Claims
-	parser    *jwt.Parser
+	req          *http.Request
+	extractor    Extractor
+	claims       jwt.Claims
+	parser       *jwt.Parser
+	validMethods []string
 }
 
 type ParseFromRequestOption func(*fromRequestParser)
@@ -68,3 +121,12 @@ func WithParser(parser *jwt.Parser) ParseFromRequestOption {
 		p.parser = parser
 	}
 }
+
+// WithValidMethods restricts the accepted signing methods, like jwt.WithValidMethods, without
+// constructing a parser. If combined with WithParser, it overrides the valid methods of the passed
+// parser, which itself is not modified.
+func WithValidMethods(methods []string) ParseFromRequestOption {
+	return func(p *fromRequestParser) {
+		p.validMethods = methods
+	}
+}
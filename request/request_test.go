@@ -1,6 +1,7 @@
 package request
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -102,3 +103,70 @@ func TestParseRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestParseAllFromRequest(t *testing.T) {
+	privateKey := test.LoadRSAPrivateKeyFromDisk("../test/sample_key")
+	publicKey := test.LoadRSAPublicKeyFromDisk("../test/sample_key.pub")
+	keyfunc := func(*jwt.Token) (interface{}, error) {
+		return publicKey, nil
+	}
+
+	tokenA := test.MakeSampleToken(jwt.MapClaims{"sub": "a"}, jwt.SigningMethodRS256, privateKey)
+	tokenB := test.MakeSampleToken(jwt.MapClaims{"sub": "b"}, jwt.SigningMethodRS256, privateKey)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", fmt.Sprintf("Bearer %v, %v, %v", tokenA, tokenB, tokenA+"x"))
+
+	results, err := ParseAllFromRequest(r, AuthorizationHeaderExtractor, keyfunc, WithClaims(&jwt.RegisteredClaims{}))
+	if err != nil {
+		t.Fatalf("ParseAllFromRequest() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("ParseAllFromRequest() returned %d results, want 3", len(results))
+	}
+
+	for i, sub := range []string{"a", "b"} {
+		if results[i].Err != nil || results[i].Line != i+1 {
+			t.Errorf("results[%d] = %+v, want valid token at position %d", i, results[i], i+1)
+			continue
+		}
+		if claims := results[i].Token.Claims.(*jwt.RegisteredClaims); claims.Subject != sub {
+			t.Errorf("results[%d] subject = %v, want %v", i, claims.Subject, sub)
+		}
+	}
+	if !errors.Is(results[2].Err, jwt.ErrTokenSignatureInvalid) {
+		t.Errorf("results[2] error = %v, want %v", results[2].Err, jwt.ErrTokenSignatureInvalid)
+	}
+
+	r.Header.Del("Authorization")
+	if _, err = ParseAllFromRequest(r, AuthorizationHeaderExtractor, keyfunc); !errors.Is(err, ErrNoTokenInRequest) {
+		t.Errorf("ParseAllFromRequest() error = %v, want %v", err, ErrNoTokenInRequest)
+	}
+}
+
+func TestParseFromRequest_WithValidMethods(t *testing.T) {
+	privateKey := test.LoadRSAPrivateKeyFromDisk("../test/sample_key")
+	publicKey := test.LoadRSAPublicKeyFromDisk("../test/sample_key.pub")
+	keyfunc := func(*jwt.Token) (interface{}, error) {
+		return publicKey, nil
+	}
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+test.MakeSampleToken(jwt.MapClaims{"foo": "bar"}, jwt.SigningMethodRS256, privateKey))
+
+	if _, err := ParseFromRequest(r, AuthorizationHeaderExtractor, keyfunc, WithValidMethods([]string{"RS256"})); err != nil {
+		t.Errorf("ParseFromRequest() error = %v", err)
+	}
+	if _, err := ParseFromRequest(r, AuthorizationHeaderExtractor, keyfunc, WithValidMethods([]string{"ES256"})); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Errorf("ParseFromRequest() error = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+	}
+
+	// The valid methods override those of a parser passed using WithParser, without modifying it
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256"}))
+	if _, err := ParseFromRequest(r, AuthorizationHeaderExtractor, keyfunc, WithValidMethods([]string{"ES256"}), WithParser(parser)); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Errorf("ParseFromRequest() error = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+	}
+	if !reflect.DeepEqual(parser.ValidMethods, []string{"RS256"}) {
+		t.Errorf("parser.ValidMethods = %v, want [RS256]", parser.ValidMethods)
+	}
+}
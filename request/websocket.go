@@ -0,0 +1,62 @@
+package request
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WebSocketProtocolExtractor extracts a token from the Sec-WebSocket-Protocol header.
+// Browsers cannot set the Authorization header on a WebSocket handshake, so clients
+// commonly offer the token as a subprotocol, following a marker subprotocol:
+//
+//	Sec-WebSocket-Protocol: jwt, <token>, chat
+//
+// Protocol is the marker, e.g. "jwt"; the entry following it is the token. If Protocol
+// is empty, the first entry is the token.
+type WebSocketProtocolExtractor struct {
+	Protocol string
+}
+
+func (e WebSocketProtocolExtractor) ExtractToken(req *http.Request) (string, error) {
+	tok, _, err := e.ExtractTokenAndProtocols(req)
+	return tok, err
+}
+
+// ExtractTokenAndProtocols works like ExtractToken, but additionally returns the remaining
+// subprotocols, i.e. all offered subprotocols except the marker and the token. The handler
+// can echo one of them, or the marker itself, back to the client.
+func (e WebSocketProtocolExtractor) ExtractTokenAndProtocols(req *http.Request) (string, []string, error) {
+	var protocols []string
+	for _, h := range req.Header.Values("Sec-WebSocket-Protocol") {
+		for _, p := range strings.Split(h, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				protocols = append(protocols, p)
+			}
+		}
+	}
+
+	start := 0
+	if e.Protocol != "" {
+		start = -1
+		for i, p := range protocols {
+			if p == e.Protocol {
+				start = i + 1
+				break
+			}
+		}
+	}
+	if start < 0 || start >= len(protocols) {
+		return "", nil, ErrNoTokenInRequest
+	}
+
+	// Drop the token and the marker directly preceding it
+	skip := start
+	if e.Protocol != "" {
+		skip--
+	}
+	var remaining []string
+	remaining = append(remaining, protocols[:skip]...)
+	remaining = append(remaining, protocols[start+1:]...)
+
+	return protocols[start], remaining, nil
+}
@@ -0,0 +1,83 @@
+package request
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestWebSocketProtocolExtractor(t *testing.T) {
+	tests := []struct {
+		name      string
+		extractor WebSocketProtocolExtractor
+		headers   []string
+		token     string
+		protocols []string
+		err       error
+	}{
+		{
+			name:      "marker and token",
+			extractor: WebSocketProtocolExtractor{"jwt"},
+			headers:   []string{"jwt, " + extractorTestTokenA},
+			token:     extractorTestTokenA,
+		},
+		{
+			name:      "additional subprotocols",
+			extractor: WebSocketProtocolExtractor{"jwt"},
+			headers:   []string{"chat, jwt," + extractorTestTokenA + ", superchat"},
+			token:     extractorTestTokenA,
+			protocols: []string{"chat", "superchat"},
+		},
+		{
+			name:      "multiple header lines",
+			extractor: WebSocketProtocolExtractor{"jwt"},
+			headers:   []string{"chat", "jwt, " + extractorTestTokenA},
+			token:     extractorTestTokenA,
+			protocols: []string{"chat"},
+		},
+		{
+			name:      "no marker configured",
+			extractor: WebSocketProtocolExtractor{},
+			headers:   []string{extractorTestTokenA + ", chat"},
+			token:     extractorTestTokenA,
+			protocols: []string{"chat"},
+		},
+		{
+			name:      "marker without token",
+			extractor: WebSocketProtocolExtractor{"jwt"},
+			headers:   []string{"chat, jwt"},
+			err:       ErrNoTokenInRequest,
+		},
+		{
+			name:      "marker missing",
+			extractor: WebSocketProtocolExtractor{"jwt"},
+			headers:   []string{"chat, " + extractorTestTokenA},
+			err:       ErrNoTokenInRequest,
+		},
+		{
+			name:      "no header",
+			extractor: WebSocketProtocolExtractor{"jwt"},
+			err:       ErrNoTokenInRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			for _, h := range tc.headers {
+				r.Header.Add("Sec-WebSocket-Protocol", h)
+			}
+
+			token, protocols, err := tc.extractor.ExtractTokenAndProtocols(r)
+			if err != tc.err {
+				t.Fatalf("Expected error '%v'.  Got '%v'", tc.err, err)
+			}
+			if token != tc.token {
+				t.Errorf("Expected token '%v'.  Got '%v'", tc.token, token)
+			}
+			if !reflect.DeepEqual(protocols, tc.protocols) {
+				t.Errorf("Expected protocols %v.  Got %v", tc.protocols, protocols)
+			}
+		})
+	}
+}
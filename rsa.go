@@ -47,21 +47,17 @@ func (m *SigningMethodRSA) Alg() string {
 // Verify implements token verification for the SigningMethod
 // For this signing method, must be an *rsa.PublicKey structure.
 func (m *SigningMethodRSA) Verify(signingString, signature string, key interface{}) error {
-	var err error
-
 	// Decode the signature
-	var sig []byte
-	if sig, err = DecodeSegment(signature); err != nil {
+	sig, err := DecodeSegment(signature)
+	if err != nil {
 		return err
 	}
 
-	var rsaKey *rsa.PublicKey
-	var ok bool
-
-	if rsaKey, ok = key.(*rsa.PublicKey); !ok {
-		return ErrInvalidKeyType
-	}
+	return m.VerifyBytes(signingString, sig, key)
+}
 
+// VerifyBytes works like Verify, but takes the already decoded signature.
+func (m *SigningMethodRSA) VerifyBytes(signingString string, sig []byte, key interface{}) error {
 	// Create hasher
 	if !m.Hash.Available() {
 		return ErrHashUnavailable
@@ -69,21 +65,31 @@ func (m *SigningMethodRSA) Verify(signingString, signature string, key interface
 	hasher := m.Hash.New()
 	hasher.Write([]byte(signingString))
 
-	// Verify the signature
-	return rsa.VerifyPKCS1v15(rsaKey, m.Hash, hasher.Sum(nil), sig)
+	return m.VerifyDigest(hasher.Sum(nil), sig, key)
 }
 
-// Sign implements token signing for the SigningMethod
-// For this signing method, must be an *rsa.PrivateKey structure.
-func (m *SigningMethodRSA) Sign(signingString string, key interface{}) (string, error) {
-	var rsaKey *rsa.PrivateKey
+// VerifyDigest implements verification of a signature of a digest computed by the caller, see
+// DigestVerifier. For this signing method, must be an *rsa.PublicKey structure.
+func (m *SigningMethodRSA) VerifyDigest(digest, sig []byte, key interface{}) error {
+	var rsaKey *rsa.PublicKey
 	var ok bool
 
-	// Validate type of key
-	if rsaKey, ok = key.(*rsa.PrivateKey); !ok {
-		return "", ErrInvalidKey
+	if rsaKey, ok = key.(*rsa.PublicKey); !ok {
+		return ErrInvalidKeyType
 	}
 
+	if err := checkDigest(m.Hash, digest); err != nil {
+		return err
+	}
+
+	// Verify the signature
+	return rsa.VerifyPKCS1v15(rsaKey, m.Hash, digest, sig)
+}
+
+// Sign implements token signing for the SigningMethod
+// For this signing method, must be an *rsa.PrivateKey structure or a crypto.Signer
+// with an *rsa.PublicKey, e.g. a key stored in an HSM.
+func (m *SigningMethodRSA) Sign(signingString string, key interface{}) (string, error) {
 	// Create the hasher
 	if !m.Hash.Available() {
 		return "", ErrHashUnavailable
@@ -92,10 +98,45 @@ func (m *SigningMethodRSA) Sign(signingString string, key interface{}) (string,
 	hasher := m.Hash.New()
 	hasher.Write([]byte(signingString))
 
-	// Sign the string and return the encoded bytes
-	if sigBytes, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, m.Hash, hasher.Sum(nil)); err == nil {
+	return m.SignDigest(hasher.Sum(nil), key)
+}
+
+// HashFunc returns the hash function used to compute the digest of the signing string.
+func (m *SigningMethodRSA) HashFunc() crypto.Hash {
+	return m.Hash
+}
+
+// SignDigest implements signing of a digest computed by the caller, see DigestSigner.
+// For this signing method, must be an *rsa.PrivateKey structure or a crypto.Signer
+func (m *SigningMethodRSA) SignDigest(digest []byte, key interface{}) (string, error) {
+	// Validate type of key
+	signer, ok := rsaSigner(key)
+	if !ok {
+		return "", ErrInvalidKey
+	}
+
+	if err := checkDigest(m.Hash, digest); err != nil {
+		return "", err
+	}
+
+	// Sign the digest and return the encoded bytes
+	if sigBytes, err := signer.Sign(rand.Reader, digest, m.Hash); err == nil {
 		return EncodeSegment(sigBytes), nil
 	} else {
 		return "", err
 	}
 }
+
+// rsaSigner returns key as a crypto.Signer, if it is an RSA private key or a crypto.Signer
+// with an RSA public key.
+func rsaSigner(key interface{}) (crypto.Signer, bool) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, true
+	case crypto.Signer:
+		_, ok := k.Public().(*rsa.PublicKey)
+		return k, ok
+	}
+
+	return nil, false
+}
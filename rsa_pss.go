@@ -82,14 +82,30 @@ func init() {
 // Verify implements token verification for the SigningMethod.
 // For this verify method, key must be an rsa.PublicKey struct
 func (m *SigningMethodRSAPSS) Verify(signingString, signature string, key interface{}) error {
-	var err error
-
 	// Decode the signature
-	var sig []byte
-	if sig, err = DecodeSegment(signature); err != nil {
+	sig, err := DecodeSegment(signature)
+	if err != nil {
 		return err
 	}
 
+	return m.VerifyBytes(signingString, sig, key)
+}
+
+// VerifyBytes works like Verify, but takes the already decoded signature.
+func (m *SigningMethodRSAPSS) VerifyBytes(signingString string, sig []byte, key interface{}) error {
+	// Create hasher
+	if !m.Hash.Available() {
+		return ErrHashUnavailable
+	}
+	hasher := m.Hash.New()
+	hasher.Write([]byte(signingString))
+
+	return m.VerifyDigest(hasher.Sum(nil), sig, key)
+}
+
+// VerifyDigest implements verification of a signature of a digest computed by the caller, see
+// DigestVerifier. For this verify method, key must be an rsa.PublicKey struct
+func (m *SigningMethodRSAPSS) VerifyDigest(digest, sig []byte, key interface{}) error {
 	var rsaKey *rsa.PublicKey
 	switch k := key.(type) {
 	case *rsa.PublicKey:
@@ -98,33 +114,22 @@ func (m *SigningMethodRSAPSS) Verify(signingString, signature string, key interf
 		return ErrInvalidKey
 	}
 
-	// Create hasher
-	if !m.Hash.Available() {
-		return ErrHashUnavailable
+	if err := checkDigest(m.Hash, digest); err != nil {
+		return err
 	}
-	hasher := m.Hash.New()
-	hasher.Write([]byte(signingString))
 
 	opts := m.Options
 	if m.VerifyOptions != nil {
 		opts = m.VerifyOptions
 	}
 
-	return rsa.VerifyPSS(rsaKey, m.Hash, hasher.Sum(nil), sig, opts)
+	return rsa.VerifyPSS(rsaKey, m.Hash, digest, sig, opts)
 }
 
 // Sign implements token signing for the SigningMethod.
-// For this signing method, key must be an rsa.PrivateKey struct
+// For this signing method, key must be an rsa.PrivateKey struct or a crypto.Signer
+// with an *rsa.PublicKey, e.g. a key stored in an HSM.
 func (m *SigningMethodRSAPSS) Sign(signingString string, key interface{}) (string, error) {
-	var rsaKey *rsa.PrivateKey
-
-	switch k := key.(type) {
-	case *rsa.PrivateKey:
-		rsaKey = k
-	default:
-		return "", ErrInvalidKeyType
-	}
-
 	// Create the hasher
 	if !m.Hash.Available() {
 		return "", ErrHashUnavailable
@@ -133,8 +138,29 @@ func (m *SigningMethodRSAPSS) Sign(signingString string, key interface{}) (strin
 	hasher := m.Hash.New()
 	hasher.Write([]byte(signingString))
 
-	// Sign the string and return the encoded bytes
-	if sigBytes, err := rsa.SignPSS(rand.Reader, rsaKey, m.Hash, hasher.Sum(nil), m.Options); err == nil {
+	return m.SignDigest(hasher.Sum(nil), key)
+}
+
+// SignDigest implements signing of a digest computed by the caller, see DigestSigner.
+// For this signing method, key must be an rsa.PrivateKey struct or a crypto.Signer
+// with an *rsa.PublicKey.
+func (m *SigningMethodRSAPSS) SignDigest(digest []byte, key interface{}) (string, error) {
+	signer, ok := rsaSigner(key)
+	if !ok {
+		return "", ErrInvalidKeyType
+	}
+
+	if err := checkDigest(m.Hash, digest); err != nil {
+		return "", err
+	}
+
+	// Sign the digest and return the encoded bytes
+	// The hash must be part of the options, when signing using crypto.Signer
+	opts := &rsa.PSSOptions{Hash: m.Hash}
+	if m.Options != nil {
+		opts.SaltLength = m.Options.SaltLength
+	}
+	if sigBytes, err := signer.Sign(rand.Reader, digest, opts); err == nil {
 		return EncodeSegment(sigBytes), nil
 	} else {
 		return "", err
@@ -1,7 +1,10 @@
 package jwt_test
 
 import (
+	"crypto"
+	"encoding/base64"
 	"io/ioutil"
+	"math/big"
 	"strings"
 	"testing"
 
@@ -63,6 +66,28 @@ func TestRSAVerify(t *testing.T) {
 	}
 }
 
+func TestRSAVerifyBytes(t *testing.T) {
+	keyData, _ := ioutil.ReadFile("test/sample_key.pub")
+	key, _ := jwt.ParseRSAPublicKeyFromPEM(keyData)
+
+	for _, data := range rsaTestData {
+		parts := strings.Split(data.tokenString, ".")
+		sig, err := jwt.DecodeSegment(parts[2])
+		if err != nil {
+			t.Fatalf("[%v] Error decoding signature: %v", data.name, err)
+		}
+
+		method := jwt.GetSigningMethod(data.alg).(*jwt.SigningMethodRSA)
+		err = method.VerifyBytes(strings.Join(parts[0:2], "."), sig, key)
+		if data.valid && err != nil {
+			t.Errorf("[%v] Error while verifying key: %v", data.name, err)
+		}
+		if !data.valid && err == nil {
+			t.Errorf("[%v] Invalid key passed validation", data.name)
+		}
+	}
+}
+
 func TestRSASign(t *testing.T) {
 	keyData, _ := ioutil.ReadFile("test/sample_key")
 	key, _ := jwt.ParseRSAPrivateKeyFromPEM(keyData)
@@ -82,6 +107,35 @@ func TestRSASign(t *testing.T) {
 	}
 }
 
+// opaqueSigner hides the concrete type of a private key, like a crypto.Signer backed by an HSM.
+type opaqueSigner struct {
+	crypto.Signer
+}
+
+func TestRSASignWithSigner(t *testing.T) {
+	keyData, _ := ioutil.ReadFile("test/sample_key")
+	key, _ := jwt.ParseRSAPrivateKeyFromPEM(keyData)
+
+	for _, method := range []jwt.SigningMethod{jwt.SigningMethodRS256, jwt.SigningMethodRS512, jwt.SigningMethodPS256, jwt.SigningMethodPS512} {
+		signingString := "eyJhbGciOiJIUzI1NiJ9.eyJmb28iOiJiYXIifQ"
+
+		sig, err := method.Sign(signingString, opaqueSigner{key})
+		if err != nil {
+			t.Fatalf("[%v] Error signing with crypto.Signer: %v", method.Alg(), err)
+		}
+		if err = method.Verify(signingString, sig, &key.PublicKey); err != nil {
+			t.Errorf("[%v] Signature of crypto.Signer is invalid: %v", method.Alg(), err)
+		}
+	}
+
+	// The signer must hold an RSA key
+	ecKeyData, _ := ioutil.ReadFile("test/ec256-private.pem")
+	ecKey, _ := jwt.ParseECPrivateKeyFromPEM(ecKeyData)
+	if _, err := jwt.SigningMethodRS256.Sign("foo", opaqueSigner{ecKey}); err == nil {
+		t.Errorf("Signing with a non-RSA crypto.Signer succeeded")
+	}
+}
+
 func TestRSAVerifyWithPreParsedPrivateKey(t *testing.T) {
 	key, _ := ioutil.ReadFile("test/sample_key.pub")
 	parsedKey, err := jwt.ParseRSAPublicKeyFromPEM(key)
@@ -198,3 +252,36 @@ func BenchmarkRS512Signing(b *testing.B) {
 
 	benchmarkSigning(b, jwt.SigningMethodRS512, parsedKey)
 }
+
+func TestParseRSAPublicKeyFromJWK(t *testing.T) {
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	n := encode(jwtTestDefaultKey.N.Bytes())
+	e := encode(big.NewInt(int64(jwtTestDefaultKey.E)).Bytes())
+
+	key, err := jwt.ParseRSAPublicKeyFromJWK(n, e)
+	if err != nil {
+		t.Fatalf("ParseRSAPublicKeyFromJWK() error = %v", err)
+	}
+	if !key.Equal(jwtTestDefaultKey) {
+		t.Fatalf("ParseRSAPublicKeyFromJWK() = %v, want %v", key, jwtTestDefaultKey)
+	}
+	if _, err = jwt.Parse(signToken(jwt.MapClaims{"foo": "bar"}, jwt.SigningMethodRS256), func(t *jwt.Token) (interface{}, error) { return key, nil }); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		n, e string
+	}{
+		{"padded modulus", n + "=", e},
+		{"invalid exponent encoding", n, "AQ+B"},
+		{"empty modulus", "", e},
+		{"exponent too small", n, encode([]byte{1})},
+		{"exponent too large", n, encode([]byte{1, 0, 0, 0, 1})},
+	}
+	for _, tc := range tests {
+		if _, err = jwt.ParseRSAPublicKeyFromJWK(tc.n, tc.e); err == nil {
+			t.Errorf("[%v] ParseRSAPublicKeyFromJWK() expected error", tc.name)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package jwt
 import (
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 	"errors"
+	"math/big"
 )
 
 var (
@@ -103,3 +105,27 @@ func ParseRSAPublicKeyFromPEM(key []byte) (*rsa.PublicKey, error) {
 
 	return pkey, nil
 }
+
+// ParseRSAPublicKeyFromJWK creates an RSA public key from the "n" (modulus) and "e" (exponent)
+// parameters of a JSON Web Key, which are base64url encoded without padding (RFC 7518, section 6.3.1)
+func ParseRSAPublicKeyFromJWK(n, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+
+	// The exponent must fit into an int, which is at least 32 bits wide
+	exponent := new(big.Int).SetBytes(eBytes)
+	if len(nBytes) == 0 || exponent.BitLen() > 31 || exponent.Int64() < 2 {
+		return nil, ErrNotRSAPublicKey
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(exponent.Int64()),
+	}, nil
+}
@@ -0,0 +1,63 @@
+package jwt
+
+import (
+	"time"
+)
+
+// NewSecureToken creates a token with opinionated defaults: it is signed using EdDSA, its "iat"
+// claim is set to the current time and its "exp" claim to ttl later, and a random "jti" claim is
+// generated using NewJTI, unless one is set already. claims must be a MapClaims or a pointer to
+// RegisteredClaims or to a struct embedding RegisteredClaims. ErrInvalidType is returned for other
+// claims, and ErrInvalidLifetime if ttl is not positive.
+//
+// Tokens created this way are meant to be parsed using ParseSecure.
+func NewSecureToken(claims Claims, ttl time.Duration, opts ...TokenOption) (*Token, error) {
+	if ttl <= 0 {
+		return nil, ErrInvalidLifetime
+	}
+
+	now := TimeFunc()
+	switch c := claims.(type) {
+	case MapClaims:
+		// Use float64, like decoded claims, so that the claims can be validated before signing
+		c["iat"] = float64(now.Unix())
+		c["exp"] = float64(now.Add(ttl).Unix())
+		if jti, ok := c["jti"].(string); !ok || jti == "" {
+			c["jti"] = NewJTI()
+		}
+	case interface{ registered() *RegisteredClaims }:
+		r := c.registered()
+		r.IssuedAt = NewNumericDate(now)
+		r.ExpiresAt = NewNumericDate(now.Add(ttl))
+		if r.ID == "" {
+			r.ID = NewJTI()
+		}
+	default:
+		return nil, ErrInvalidType
+	}
+
+	return NewWithClaims(SigningMethodEdDSA, claims, opts...), nil
+}
+
+// ParseSecure parses a token created using NewSecureToken, enabling the hardening options that
+// match its defaults: only EdDSA is accepted and the key returned by keyFunc must be an
+// ed25519.PublicKey, the "none" signing method is rejected, and the "exp" and "jti" claims are
+// required. Further options, e.g. WithIssuer, are applied before these, so that they cannot relax
+// them: WithValidMethods cannot allow other signing methods and WithoutClaimsValidation is ignored.
+func ParseSecure(tokenString string, claims Claims, keyFunc Keyfunc, options ...ParserOption) (*Token, error) {
+	secure := []ParserOption{
+		WithValidMethods([]string{SigningMethodEdDSA.Alg()}),
+		WithStrictKeyTypeCheck(),
+		WithRejectNone(),
+		WithExpirationRequired(),
+		WithRequireJTI(),
+	}
+
+	p := NewParser(options...)
+	for _, option := range secure {
+		option(p)
+	}
+	p.SkipClaimsValidation = false
+
+	return p.ParseWithClaims(tokenString, claims, keyFunc)
+}
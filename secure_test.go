@@ -0,0 +1,165 @@
+package jwt_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestSecureToken(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFunc := func(*jwt.Token) (interface{}, error) { return publicKey, nil }
+
+	type customClaims struct {
+		Role string `json:"role"`
+		jwt.RegisteredClaims
+	}
+
+	tests := []struct {
+		name   string
+		claims jwt.Claims
+		parsed jwt.Claims
+	}{
+		{"map claims", jwt.MapClaims{"sub": "foo"}, jwt.MapClaims{}},
+		{"registered claims", &jwt.RegisteredClaims{Subject: "foo"}, &jwt.RegisteredClaims{}},
+		{"custom claims", &customClaims{Role: "admin", RegisteredClaims: jwt.RegisteredClaims{Subject: "foo"}}, &customClaims{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := jwt.NewSecureToken(tc.claims, time.Hour)
+			if err != nil {
+				t.Fatalf("NewSecureToken() error = %v", err)
+			}
+			if token.Method != jwt.SigningMethodEdDSA {
+				t.Errorf("NewSecureToken() method = %v, want EdDSA", token.Alg())
+			}
+			if err = token.Claims.Valid(); err != nil {
+				t.Errorf("Valid() error = %v", err)
+			}
+
+			tokenString, err := token.SignedString(privateKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, err := jwt.ParseSecure(tokenString, tc.parsed, keyFunc)
+			if err != nil {
+				t.Fatalf("ParseSecure() error = %v", err)
+			}
+			if sub, _ := parsed.Subject(); sub != "foo" {
+				t.Errorf("ParseSecure() sub = %v, want foo", sub)
+			}
+			if jti, ok := parsed.ID(); !ok || jti == "" {
+				t.Errorf("ParseSecure() jti = %v, want random identifier", jti)
+			}
+		})
+	}
+
+	if _, err = jwt.NewSecureToken(jwt.MapClaims{}, 0); !errors.Is(err, jwt.ErrInvalidLifetime) {
+		t.Errorf("NewSecureToken() error = %v, want %v", err, jwt.ErrInvalidLifetime)
+	}
+	if _, err = jwt.NewSecureToken(jwt.StandardClaims{}, time.Hour); !errors.Is(err, jwt.ErrInvalidType) {
+		t.Errorf("NewSecureToken() error = %v, want %v", err, jwt.ErrInvalidType)
+	}
+}
+
+func TestParseSecure_Hardening(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFunc := func(*jwt.Token) (interface{}, error) { return publicKey, nil }
+	exp := jwt.NewNumericDate(time.Now().Add(time.Hour))
+
+	sign := func(method jwt.SigningMethod, claims jwt.Claims, key interface{}) string {
+		tokenString, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tokenString
+	}
+
+	tests := []struct {
+		name        string
+		tokenString string
+		keyFunc     jwt.Keyfunc
+		options     []jwt.ParserOption
+		err         error
+	}{
+		{
+			name:        "valid",
+			tokenString: sign(jwt.SigningMethodEdDSA, &jwt.RegisteredClaims{ExpiresAt: exp, ID: "1"}, privateKey),
+			keyFunc:     keyFunc,
+		},
+		{
+			name:        "exp missing",
+			tokenString: sign(jwt.SigningMethodEdDSA, &jwt.RegisteredClaims{ID: "1"}, privateKey),
+			keyFunc:     keyFunc,
+			err:         jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:        "jti missing",
+			tokenString: sign(jwt.SigningMethodEdDSA, &jwt.RegisteredClaims{ExpiresAt: exp}, privateKey),
+			keyFunc:     keyFunc,
+			err:         jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:        "other signing method",
+			tokenString: sign(jwt.SigningMethodHS256, &jwt.RegisteredClaims{ExpiresAt: exp, ID: "1"}, hmacTestKey),
+			keyFunc:     func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil },
+			err:         jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:        "none",
+			tokenString: sign(jwt.SigningMethodNone, &jwt.RegisteredClaims{ExpiresAt: exp, ID: "1"}, jwt.UnsafeAllowNoneSignatureType),
+			keyFunc:     func(*jwt.Token) (interface{}, error) { return jwt.UnsafeAllowNoneSignatureType, nil },
+			err:         jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:        "key of invalid type",
+			tokenString: sign(jwt.SigningMethodEdDSA, &jwt.RegisteredClaims{ExpiresAt: exp, ID: "1"}, privateKey),
+			keyFunc:     func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil },
+			err:         jwt.ErrInvalidKeyType,
+		},
+		{
+			name:        "exp missing without claims validation",
+			tokenString: sign(jwt.SigningMethodEdDSA, &jwt.RegisteredClaims{ID: "1"}, privateKey),
+			keyFunc:     keyFunc,
+			options:     []jwt.ParserOption{jwt.WithoutClaimsValidation()},
+			err:         jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:        "other signing method with valid methods",
+			tokenString: sign(jwt.SigningMethodHS256, &jwt.RegisteredClaims{ExpiresAt: exp, ID: "1"}, hmacTestKey),
+			keyFunc:     func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil },
+			options:     []jwt.ParserOption{jwt.WithValidMethods([]string{"EdDSA", "HS256"})},
+			err:         jwt.ErrTokenSignatureInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := jwt.ParseSecure(tc.tokenString, &jwt.RegisteredClaims{}, tc.keyFunc, tc.options...)
+			if tc.err == nil && err != nil {
+				t.Errorf("ParseSecure() unexpected error = %v", err)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Errorf("ParseSecure() error = %v, want %v", err, tc.err)
+			}
+		})
+	}
+
+	// Further options are applied
+	tokenString := sign(jwt.SigningMethodEdDSA, &jwt.RegisteredClaims{ExpiresAt: exp, ID: "1"}, privateKey)
+	if _, err = jwt.ParseSecure(tokenString, &jwt.RegisteredClaims{}, keyFunc, jwt.WithIssuer("foo")); !errors.Is(err, jwt.ErrTokenInvalidIssuer) {
+		t.Errorf("ParseSecure() error = %v, want %v", err, jwt.ErrTokenInvalidIssuer)
+	}
+}
@@ -1,10 +1,12 @@
 package jwt
 
 import (
+	"crypto"
 	"sync"
 )
 
 var signingMethods = map[string]func() SigningMethod{}
+var algorithmAliases = map[string]string{}
 var signingMethodLock = new(sync.RWMutex)
 
 // SigningMethod can be used add new methods for signing or verifying tokens.
@@ -14,6 +16,40 @@ type SigningMethod interface {
 	Alg() string                                                   // returns the alg identifier for this method (example: 'HS256')
 }
 
+// bytesVerifier is implemented by signing methods that can verify an already decoded signature.
+// All signing methods of this package implement it.
+type bytesVerifier interface {
+	VerifyBytes(signingString string, sig []byte, key interface{}) error
+}
+
+// DigestSigner is implemented by signing methods that can sign a digest of the signing string,
+// which was computed by the caller using HashFunc, like a crypto.Signer does. This avoids hashing
+// very large payloads twice, e.g. if they are hashed while being streamed. The RSA, RSA-PSS and
+// ECDSA signing methods of this package implement it; HMAC and EdDSA cannot sign digests.
+type DigestSigner interface {
+	HashFunc() crypto.Hash                                     // Returns the hash function for computing the digest
+	SignDigest(digest []byte, key interface{}) (string, error) // Returns encoded signature or error
+}
+
+// DigestVerifier is implemented by signing methods that can verify a signature of a digest of the
+// signing string, which was computed by the caller using HashFunc, see DigestSigner. The RSA,
+// RSA-PSS and ECDSA signing methods of this package implement it.
+type DigestVerifier interface {
+	HashFunc() crypto.Hash                                  // Returns the hash function for computing the digest
+	VerifyDigest(digest, sig []byte, key interface{}) error // Returns nil if the decoded signature is valid
+}
+
+// checkDigest checks that digest has the size of hash.
+func checkDigest(hash crypto.Hash, digest []byte) error {
+	if !hash.Available() {
+		return ErrHashUnavailable
+	}
+	if len(digest) != hash.Size() {
+		return ErrInvalidDigest
+	}
+	return nil
+}
+
 // RegisterSigningMethod registers the "alg" name and a factory function for signing method.
 // This is typically done during init() in the method's implementation
 func RegisterSigningMethod(alg string, f func() SigningMethod) {
@@ -23,12 +59,28 @@ func RegisterSigningMethod(alg string, f func() SigningMethod) {
 	signingMethods[alg] = f
 }
 
+// RegisterAlgorithmAlias registers alias as an alternative "alg" name for the signing method
+// registered as canonical, e.g. to verify tokens of an issuer using the nonstandard name "RSA256"
+// for "RS256". GetSigningMethod returns the canonical signing method for alias, so that parsed
+// tokens are checked against WithValidMethods and signed using the canonical name. An alias has no
+// effect for names that are registered as signing methods themselves.
+func RegisterAlgorithmAlias(alias, canonical string) {
+	signingMethodLock.Lock()
+	defer signingMethodLock.Unlock()
+
+	algorithmAliases[alias] = canonical
+}
+
 // GetSigningMethod retrieves a signing method from an "alg" string
 func GetSigningMethod(alg string) (method SigningMethod) {
 	signingMethodLock.RLock()
 	defer signingMethodLock.RUnlock()
 
-	if methodF, ok := signingMethods[alg]; ok {
+	methodF, ok := signingMethods[alg]
+	if !ok {
+		methodF, ok = signingMethods[algorithmAliases[alg]]
+	}
+	if ok {
 		method = methodF()
 	}
 	return
@@ -0,0 +1,103 @@
+package jwt_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestRegisterAlgorithmAlias(t *testing.T) {
+	jwt.RegisterAlgorithmAlias("RSA256", "RS256")
+	jwt.RegisterAlgorithmAlias("HS256", "RS256")
+
+	if method := jwt.GetSigningMethod("RSA256"); method != jwt.SigningMethodRS256 {
+		t.Fatalf("GetSigningMethod(RSA256) = %v, want %v", method, jwt.SigningMethodRS256)
+	}
+	if method := jwt.GetSigningMethod("HS256"); method != jwt.SigningMethodHS256 {
+		t.Errorf("GetSigningMethod(HS256) = %v, registered methods must not be aliased", method)
+	}
+	for _, alg := range jwt.GetAlgorithms() {
+		if alg == "RSA256" {
+			t.Errorf("GetAlgorithms() contains alias %v", alg)
+		}
+	}
+
+	// A token labeled with the alias is verified as the canonical method
+	parts := strings.Split(signToken(jwt.MapClaims{"foo": "bar"}, jwt.SigningMethodRS256), ".")
+	header := jwt.EncodeSegment([]byte(`{"alg":"RSA256","typ":"JWT"}`))
+	sig, err := jwt.SigningMethodRS256.Sign(header+"."+parts[1], jwtTestRSAPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenString := header + "." + parts[1] + "." + sig
+
+	token, err := jwt.Parse(tokenString, defaultKeyFunc, jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil || token.Method != jwt.SigningMethodRS256 {
+		t.Fatalf("Parse() error = %v, method = %v", err, token.Method)
+	}
+	if _, err = jwt.Parse(tokenString, defaultKeyFunc, jwt.WithValidMethods([]string{"RSA256"})); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Errorf("Parse() error = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+	}
+
+	// Tokens are signed using the canonical name
+	reissued := jwt.NewWithClaims(jwt.GetSigningMethod("RSA256"), token.Claims)
+	if reissued.Header["alg"] != "RS256" {
+		t.Errorf("alg header = %v, want RS256", reissued.Header["alg"])
+	}
+}
+
+func TestDigestSigner(t *testing.T) {
+	tests := []struct {
+		method     jwt.SigningMethod
+		privateKey interface{}
+		publicKey  interface{}
+	}{
+		{jwt.SigningMethodRS256, jwtTestRSAPrivateKey, jwtTestDefaultKey},
+		{jwt.SigningMethodPS384, jwtTestRSAPrivateKey, jwtTestDefaultKey},
+		{jwt.SigningMethodES256, jwtTestEC256PrivateKey, jwtTestEC256PublicKey},
+		{jwt.SigningMethodES256Deterministic, jwtTestEC256PrivateKey, jwtTestEC256PublicKey},
+	}
+
+	signingString := "eyJhbGciOiJub25lIn0.eyJmb28iOiJiYXIifQ"
+
+	for _, tc := range tests {
+		t.Run(tc.method.Alg(), func(t *testing.T) {
+			signer, ok := tc.method.(jwt.DigestSigner)
+			if !ok {
+				t.Fatalf("%T does not implement DigestSigner", tc.method)
+			}
+
+			hasher := signer.HashFunc().New()
+			hasher.Write([]byte(signingString))
+			digest := hasher.Sum(nil)
+
+			sig, err := signer.SignDigest(digest, tc.privateKey)
+			if err != nil {
+				t.Fatalf("SignDigest() error = %v", err)
+			}
+			if err = tc.method.Verify(signingString, sig, tc.publicKey); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+
+			if _, err = signer.SignDigest(digest[1:], tc.privateKey); !errors.Is(err, jwt.ErrInvalidDigest) {
+				t.Errorf("SignDigest() error = %v, want %v", err, jwt.ErrInvalidDigest)
+			}
+		})
+	}
+
+	// Deterministic signatures do not depend on how the digest was computed
+	sig, _ := jwt.SigningMethodES256Deterministic.Sign(signingString, jwtTestEC256PrivateKey)
+	digest := sha256.Sum256([]byte(signingString))
+	if other, _ := jwt.SigningMethodES256Deterministic.SignDigest(digest[:], jwtTestEC256PrivateKey); other != sig {
+		t.Errorf("SignDigest() = %v, want %v", other, sig)
+	}
+
+	for _, method := range []jwt.SigningMethod{jwt.SigningMethodHS256, jwt.SigningMethodEdDSA} {
+		if _, ok := method.(jwt.DigestSigner); ok {
+			t.Errorf("%T unexpectedly implements DigestSigner", method)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
 	"encoding/base64"
 	"encoding/json"
 	"strings"
@@ -15,6 +18,9 @@ import (
 // To use the non-recommended decoding, set this boolean to `true` prior to using this package.
 var DecodePaddingAllowed bool
 
+// base32Encoding is the unpadded base32 encoding of segments, see WithBase32Encoding.
+var base32Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
 // TimeFunc provides the current time when parsing token to validate "exp" claim (expiration time).
 // You can override it to use another time value.  This is useful for testing or if your
 // server uses a different time zone than your tokens.
@@ -29,22 +35,30 @@ type Keyfunc func(*Token) (interface{}, error)
 // Token represents a JWT Token.  Different fields will be used depending on whether you're
 // creating or parsing/verifying a token.
 type Token struct {
-	Raw       string                 // The raw token.  Populated when you Parse a token
-	Method    SigningMethod          // The signing method used or to be used
-	Header    map[string]interface{} // The first segment of the token
-	Claims    Claims                 // The second segment of the token
-	Signature string                 // The third segment of the token.  Populated when you Parse a token
-	Valid     bool                   // Is the token valid?  Populated when you Parse/Verify a token
+	Raw          string                 // The raw token.  Populated when you Parse a token
+	Method       SigningMethod          // The signing method used or to be used
+	Header       map[string]interface{} // The first segment of the token
+	Claims       Claims                 // The second segment of the token
+	Signature    string                 // The third segment of the token.  Populated when you Parse a token
+	RawClaims    []byte                 // The decoded second segment of the token, as JSON.  Populated when you Parse a token
+	SigningInput string                 // The first two segments of the token (header.claims), which are signed.  Populated when you Parse a token
+	Valid        bool                   // Is the token valid?  Populated when you Parse/Verify a token
+	UsedPadding  bool                   // Does any segment use base64 padding?  Populated when you Parse a token with DecodePaddingAllowed
+
+	noHTMLEscape bool // Do not escape HTML characters when encoding the token, see WithoutHTMLEscaping
+	base32       bool // Segments are encoded using unpadded base32, see WithBase32Segments and WithBase32Encoding
 }
 
 // New creates a new Token with the specified signing method and an empty map of claims.
-func New(method SigningMethod) *Token {
-	return NewWithClaims(method, MapClaims{})
+// Additional options can be specified to modify the token, e.g. its header.
+func New(method SigningMethod, opts ...TokenOption) *Token {
+	return NewWithClaims(method, MapClaims{}, opts...)
 }
 
 // NewWithClaims creates a new Token with the specified signing method and claims.
-func NewWithClaims(method SigningMethod, claims Claims) *Token {
-	return &Token{
+// Additional options can be specified to modify the token, e.g. its header.
+func NewWithClaims(method SigningMethod, claims Claims, opts ...TokenOption) *Token {
+	t := &Token{
 		Header: map[string]interface{}{
 			"typ": "JWT",
 			"alg": method.Alg(),
@@ -52,22 +66,185 @@ func NewWithClaims(method SigningMethod, claims Claims) *Token {
 		Claims: claims,
 		Method: method,
 	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// WithMethod returns a copy of the token, which is to be signed using method, e.g. to re-issue a
+// token with a different algorithm. The header of the copy is a shallow copy with "alg" updated,
+// while the claims are shared with the original token. Fields populated when parsing a token,
+// such as Raw and Signature, are not copied. The original token is not modified.
+func (t *Token) WithMethod(method SigningMethod) *Token {
+	header := make(map[string]interface{}, len(t.Header))
+	for k, v := range t.Header {
+		header[k] = v
+	}
+	header["alg"] = method.Alg()
+
+	return &Token{
+		Method:       method,
+		Header:       header,
+		Claims:       t.Claims,
+		noHTMLEscape: t.noHTMLEscape,
+		base32:       t.base32,
+	}
+}
+
+// Issuer returns the iss claim, if the claims provide a non-empty issuer through a GetIssuer
+// method, like RegisteredClaims and MapClaims do.
+func (t *Token) Issuer() (string, bool) {
+	c, ok := t.Claims.(interface{ GetIssuer() (string, error) })
+	if !ok {
+		return "", false
+	}
+	iss, err := c.GetIssuer()
+	return iss, err == nil && iss != ""
+}
+
+// Subject returns the sub claim, if the claims provide a non-empty subject through a GetSubject
+// method, like RegisteredClaims and MapClaims do.
+func (t *Token) Subject() (string, bool) {
+	c, ok := t.Claims.(interface{ GetSubject() (string, error) })
+	if !ok {
+		return "", false
+	}
+	sub, err := c.GetSubject()
+	return sub, err == nil && sub != ""
+}
+
+// Audiences returns the aud claim, if the claims provide a non-empty audience through a
+// GetAudience method, like RegisteredClaims and MapClaims do.
+func (t *Token) Audiences() ([]string, bool) {
+	c, ok := t.Claims.(interface{ GetAudience() (ClaimStrings, error) })
+	if !ok {
+		return nil, false
+	}
+	aud, err := c.GetAudience()
+	return aud, err == nil && len(aud) > 0
+}
+
+// ID returns the jti claim, if the claims provide a non-empty ID through a GetID method, like
+// RegisteredClaims and MapClaims do.
+func (t *Token) ID() (string, bool) {
+	c, ok := t.Claims.(interface{ GetID() (string, error) })
+	if !ok {
+		return "", false
+	}
+	jti, err := c.GetID()
+	return jti, err == nil && jti != ""
+}
+
+// Alg returns the algorithm of the token's signing method, e.g. to collect metrics about the
+// algorithms of incoming tokens. Parsing always resolves Method from the "alg" header using the
+// registered signing methods, so for parsed tokens this is the canonical name of the algorithm,
+// even if the header uses an alias registered with RegisterAlgorithmAlias. If Method is not set,
+// the "alg" header is returned instead.
+func (t *Token) Alg() string {
+	if t.Method != nil {
+		return t.Method.Alg()
+	}
+	alg, _ := t.Header["alg"].(string)
+	return alg
+}
+
+// SignatureBytes returns the decoded signature of a parsed token. Like parsing, it honors
+// DecodePaddingAllowed, and decodes base32 for tokens parsed using WithBase32Encoding.
+func (t *Token) SignatureBytes() ([]byte, error) {
+	if t.base32 {
+		return base32Encoding.DecodeString(strings.ToUpper(t.Signature))
+	}
+	return DecodeSegment(t.Signature)
 }
 
 // SignedString creates and returns a complete, signed JWT.
 // The token is signed using the SigningMethod specified in the token.
 func (t *Token) SignedString(key interface{}) (string, error) {
-	var sig, sstr string
-	var err error
-	if sstr, err = t.SigningString(); err != nil {
+	sstr, sig, err := t.sign(key)
+	if err != nil {
 		return "", err
 	}
-	if sig, err = t.Method.Sign(sstr, key); err != nil {
+	if sig, err = t.encodeSignature(sig); err != nil {
+		return "", err
+	}
+	return strings.Join([]string{sstr, sig}, "."), nil
+}
+
+// SignAndVerify creates and returns a complete, signed JWT like SignedString, but only after
+// verifying the signature using publicKey. This catches mismatched key pairs or methods before the
+// token is handed out. For HMAC, publicKey is the same secret as privateKey.
+func (t *Token) SignAndVerify(privateKey, publicKey interface{}) (string, error) {
+	sstr, sig, err := t.sign(privateKey)
+	if err != nil {
+		return "", err
+	}
+	if err = t.Method.Verify(sstr, sig, publicKey); err != nil {
+		return "", err
+	}
+	if sig, err = t.encodeSignature(sig); err != nil {
 		return "", err
 	}
 	return strings.Join([]string{sstr, sig}, "."), nil
 }
 
+// SignedJSON creates and returns a complete, signed JWT in the flattened JWS JSON serialization,
+// as referenced at https://datatracker.ietf.org/doc/html/rfc7515#section-7.2.2.
+// The token is signed using the SigningMethod specified in the token.
+func (t *Token) SignedJSON(key interface{}) ([]byte, error) {
+	sstr, sig, err := t.sign(key)
+	if err != nil {
+		return nil, err
+	}
+	if sig, err = t.encodeSignature(sig); err != nil {
+		return nil, err
+	}
+	parts := strings.Split(sstr, ".")
+	return json.Marshal(flattenedJSON{
+		Protected: parts[0],
+		Payload:   parts[1],
+		Signature: sig,
+	})
+}
+
+// sign returns the signing string and the encoded signature. The "alg" header must match the
+// signing method, so that the token cannot claim an algorithm it is not signed with.
+func (t *Token) sign(key interface{}) (sstr, sig string, err error) {
+	if alg, _ := t.Header["alg"].(string); alg != t.Method.Alg() {
+		return "", "", ErrTokenAlgMismatch
+	}
+	if sstr, err = t.SigningString(); err != nil {
+		return "", "", err
+	}
+	if sig, err = t.Method.Sign(sstr, key); err != nil {
+		return "", "", err
+	}
+	return sstr, sig, nil
+}
+
+// encodeSignature re-encodes the base64url encoded signature produced by a signing method using
+// the segment encoding of the token.
+func (t *Token) encodeSignature(sig string) (string, error) {
+	if !t.base32 {
+		return sig, nil
+	}
+	b, err := DecodeSegment(sig)
+	if err != nil {
+		return "", err
+	}
+	return base32Encoding.EncodeToString(b), nil
+}
+
+// encodeSegment encodes a segment of the token, using base64url unless WithBase32Segments is set.
+func (t *Token) encodeSegment(seg []byte) string {
+	if t.base32 {
+		return base32Encoding.EncodeToString(seg)
+	}
+	return EncodeSegment(seg)
+}
+
 // SigningString generates the signing string.  This is the
 // most expensive part of the whole deal.  Unless you
 // need this for something special, just go straight for
@@ -76,19 +253,89 @@ func (t *Token) SigningString() (string, error) {
 	var err error
 	var jsonValue []byte
 
-	if jsonValue, err = json.Marshal(t.Header); err != nil {
+	if jsonValue, err = t.marshal(t.Header); err != nil {
+		return "", err
+	}
+	header := t.encodeSegment(jsonValue)
+
+	if jsonValue, err = t.marshal(t.Claims); err != nil {
 		return "", err
 	}
-	header := EncodeSegment(jsonValue)
 
-	if jsonValue, err = json.Marshal(t.Claims); err != nil {
+	unencoded, err := unencodedPayload(t.Header)
+	if err != nil {
 		return "", err
 	}
-	claim := EncodeSegment(jsonValue)
+
+	var claim string
+	if unencoded {
+		if bytes.IndexByte(jsonValue, '.') >= 0 {
+			return "", ErrUnencodedPayloadPeriod
+		}
+		claim = string(jsonValue)
+	} else {
+		claim = t.encodeSegment(jsonValue)
+	}
 
 	return strings.Join([]string{header, claim}, "."), nil
 }
 
+// marshal returns the JSON encoding of v, like json.Marshal, but without escaping HTML characters
+// if so requested by WithoutHTMLEscaping.
+func (t *Token) marshal(v interface{}) ([]byte, error) {
+	if !t.noHTMLEscape {
+		return json.Marshal(v)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	// Encode terminates the value with a newline, which json.Marshal does not
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// unencodedPayload reports whether header specifies an unencoded payload, i.e. "b64" is false,
+// as referenced at https://datatracker.ietf.org/doc/html/rfc7797. The payload is then used as is
+// in the signing input and in the compact serialization, which rules out payloads containing '.'.
+// As required by the RFC, "b64" must also be listed in the "crit" header.
+func unencodedPayload(header map[string]interface{}) (bool, error) {
+	v, ok := header["b64"]
+	if !ok {
+		return false, nil
+	}
+
+	b64, ok := v.(bool)
+	if !ok {
+		return false, ErrInvalidB64Header
+	}
+	if b64 {
+		return false, nil
+	}
+
+	// crit is a []string when set by the issuer, and a []interface{} when parsed
+	var crit []interface{}
+	switch v := header["crit"].(type) {
+	case []interface{}:
+		crit = v
+	case []string:
+		for _, c := range v {
+			crit = append(crit, c)
+		}
+	}
+
+	for _, c := range crit {
+		if c == "b64" {
+			return true, nil
+		}
+	}
+
+	return false, ErrInvalidB64Header
+}
+
 // Parse parses, validates, verifies the signature and returns the parsed token.
 // keyFunc will receive the parsed token and should return the cryptographic key
 // for verifying the signature.
@@ -104,6 +351,41 @@ func ParseWithClaims(tokenString string, claims Claims, keyFunc Keyfunc, options
 	return NewParser(options...).ParseWithClaims(tokenString, claims, keyFunc)
 }
 
+// ParseAndBind parses, verifies and validates tokenString like ParseWithClaims, decoding the
+// claims into dst, which must be a pointer to a Claims implementation (or a non-nil MapClaims).
+// It is meant for callers that only need the claims. dst must not be used if an error is
+// returned, since it may have been populated nevertheless.
+func ParseAndBind(tokenString string, keyFunc Keyfunc, dst Claims, options ...ParserOption) error {
+	_, err := NewParser(options...).ParseWithClaims(tokenString, dst, keyFunc)
+	return err
+}
+
+// ParseJSON parses a token in the flattened JWS JSON serialization, as produced by SignedJSON.
+// Apart from the serialization, it behaves exactly like Parse.
+func ParseJSON(data []byte, keyFunc Keyfunc, options ...ParserOption) (*Token, error) {
+	return NewParser(options...).ParseJSON(data, keyFunc)
+}
+
+func ParseJSONWithClaims(data []byte, claims Claims, keyFunc Keyfunc, options ...ParserOption) (*Token, error) {
+	return NewParser(options...).ParseJSONWithClaims(data, claims, keyFunc)
+}
+
+// flattenedJSON is the flattened JWS JSON serialization of a token. Unprotected header
+// parameters are not supported.
+type flattenedJSON struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// TokenHash returns the base64url encoded SHA-256 hash of tokenString, e.g. to identify tokens in a
+// cache or revocation list without storing them. The token is not decoded, so any two different
+// strings have different hashes, even if they represent the same token.
+func TokenHash(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
 // EncodeSegment encodes a JWT specific base64url encoding with padding stripped
 //
 // Deprecated: In a future release, we will demote this function to a non-exported function, since it
@@ -0,0 +1,51 @@
+package jwt
+
+// TokenOption is used to implement functional-style options that modify a token created by New or
+// NewWithClaims. To add new options, just create a function (ideally beginning with With or Without)
+// that returns an anonymous function that takes a *Token type as input and manipulates it accordingly.
+type TokenOption func(*Token)
+
+// WithTokenType is an option to set the "typ" header to typ instead of the default "JWT", e.g. to
+// "at+jwt" for access tokens as specified in RFC 9068, or "JOSE" for JWS that are not JWTs. If typ
+// is empty, the "typ" header is omitted.
+func WithTokenType(typ string) TokenOption {
+	return func(t *Token) {
+		if typ == "" {
+			delete(t.Header, "typ")
+			return
+		}
+		t.Header["typ"] = typ
+	}
+}
+
+// WithHeader is an option to merge header into the default header of the token, e.g. to set "typ"
+// and "kid" in one call. The "alg" header always reflects the signing method of the token and is
+// therefore not overwritten.
+func WithHeader(header map[string]interface{}) TokenOption {
+	return func(t *Token) {
+		for k, v := range header {
+			if k != "alg" {
+				t.Header[k] = v
+			}
+		}
+	}
+}
+
+// WithBase32Segments is an option to encode the segments of the token using unpadded base32
+// (RFC 4648) instead of base64url, for parsers using WithBase32Encoding. Like that option, this is
+// not compliant with RFC 7519 and only meant to interoperate with systems that cannot transport
+// mixed-case tokens.
+func WithBase32Segments() TokenOption {
+	return func(t *Token) {
+		t.base32 = true
+	}
+}
+
+// WithoutHTMLEscaping is an option to encode the header and claims without escaping the HTML
+// characters <, > and &, which json.Marshal escapes by default. This produces the same encoding as
+// e.g. JSON.stringify in JavaScript.
+func WithoutHTMLEscaping() TokenOption {
+	return func(t *Token) {
+		t.noHTMLEscape = true
+	}
+}
@@ -0,0 +1,88 @@
+package jwt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestWithTokenType(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []jwt.TokenOption
+		want interface{}
+	}{
+		{"default", nil, "JWT"},
+		{"access token", []jwt.TokenOption{jwt.WithTokenType("at+jwt")}, "at+jwt"},
+		{"jose", []jwt.TokenOption{jwt.WithTokenType("JOSE")}, "JOSE"},
+		{"omitted", []jwt.TokenOption{jwt.WithTokenType("")}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenString, err := jwt.New(jwt.SigningMethodHS256, tc.opts...).SignedString(hmacTestKey)
+			if err != nil {
+				t.Fatalf("SignedString() error = %v", err)
+			}
+
+			token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := token.Header["typ"]; got != tc.want {
+				t.Errorf("typ header = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	header := map[string]interface{}{"typ": "at+jwt", "kid": "key-1", "alg": "none"}
+	want := map[string]interface{}{"typ": "at+jwt", "kid": "key-1", "alg": "HS256"}
+
+	for _, opts := range [][]jwt.TokenOption{
+		{jwt.WithHeader(header)},
+		{jwt.WithHeader(header), jwt.WithTokenType("at+jwt")},
+		{jwt.WithTokenType("JWT"), jwt.WithHeader(header)},
+	} {
+		token := jwt.New(jwt.SigningMethodHS256, opts...)
+		if !reflect.DeepEqual(token.Header, want) {
+			t.Errorf("Header = %v, want %v", token.Header, want)
+		}
+	}
+
+	if _, ok := header["kid"]; !ok || header["alg"] != "none" {
+		t.Errorf("WithHeader() modified its argument: %v", header)
+	}
+}
+
+func TestWithoutHTMLEscaping(t *testing.T) {
+	claims := jwt.MapClaims{"redirect": "https://example.com/?a=1&b=<2>"}
+
+	tests := []struct {
+		name string
+		opts []jwt.TokenOption
+		want string
+	}{
+		{"default", nil, `{"redirect":"https://example.com/?a=1\u0026b=\u003c2\u003e"}`},
+		{"without escaping", []jwt.TokenOption{jwt.WithoutHTMLEscaping()}, `{"redirect":"https://example.com/?a=1&b=<2>"}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims, tc.opts...).SignedString(hmacTestKey)
+			if err != nil {
+				t.Fatalf("SignedString() error = %v", err)
+			}
+
+			token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := string(token.RawClaims); got != tc.want {
+				t.Errorf("claims = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package jwt_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -77,3 +83,341 @@ func BenchmarkToken_SigningString(b *testing.B) {
 		}
 	})
 }
+
+func TestToken_SignedJSON(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
+
+	data, err := token.SignedJSON(hmacTestKey)
+	if err != nil {
+		t.Fatalf("SignedJSON() error = %v", err)
+	}
+
+	var jws map[string]string
+	if err = json.Unmarshal(data, &jws); err != nil {
+		t.Fatalf("SignedJSON() produced invalid JSON: %v", err)
+	}
+
+	compact, _ := token.SignedString(hmacTestKey)
+	if want := strings.Join([]string{jws["protected"], jws["payload"], jws["signature"]}, "."); want != compact {
+		t.Errorf("SignedJSON() got = %v, want segments of %v", jws, compact)
+	}
+
+	parsed, err := jwt.ParseJSON(data, func(t *jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+	if err != nil || !parsed.Valid {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+	if parsed.Claims.(jwt.MapClaims)["foo"] != "bar" {
+		t.Errorf("ParseJSON() claims = %v", parsed.Claims)
+	}
+}
+
+func TestParseJSON_Malformed(t *testing.T) {
+	for _, data := range []string{`[]`, `not json`, `{"protected":"a.b","payload":"e30","signature":""}`} {
+		_, err := jwt.ParseJSON([]byte(data), func(t *jwt.Token) (interface{}, error) { return hmacTestKey, nil })
+		if !errors.Is(err, jwt.ErrTokenMalformed) {
+			t.Errorf("ParseJSON(%s) error = %v, want %v", data, err, jwt.ErrTokenMalformed)
+		}
+	}
+}
+
+func TestToken_UnencodedPayload(t *testing.T) {
+	keyFunc := func(t *jwt.Token) (interface{}, error) { return hmacTestKey, nil }
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
+	token.Header["b64"] = false
+	token.Header["crit"] = []string{"b64"}
+
+	tokenString, err := token.SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	if parts := strings.Split(tokenString, "."); parts[1] != `{"foo":"bar"}` {
+		t.Fatalf("SignedString() payload = %v, want unencoded payload", parts[1])
+	}
+
+	parsed, err := jwt.Parse(tokenString, keyFunc)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if claims := parsed.Claims.(jwt.MapClaims); claims["foo"] != "bar" {
+		t.Errorf("Parse() claims = %v", claims)
+	}
+
+	// The signature covers the unencoded payload
+	if _, err = jwt.Parse(strings.Replace(tokenString, "bar", "baz", 1), keyFunc); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Errorf("Parse() error = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+	}
+
+	// A period in the payload cannot be serialized
+	token.Claims = jwt.MapClaims{"foo": "b.r"}
+	if _, err = token.SignedString(hmacTestKey); !errors.Is(err, jwt.ErrUnencodedPayloadPeriod) {
+		t.Errorf("SignedString() error = %v, want %v", err, jwt.ErrUnencodedPayloadPeriod)
+	}
+
+	// b64 must be listed in crit
+	delete(token.Header, "crit")
+	if _, err = token.SignedString(hmacTestKey); !errors.Is(err, jwt.ErrInvalidB64Header) {
+		t.Errorf("SignedString() error = %v, want %v", err, jwt.ErrInvalidB64Header)
+	}
+
+	header := jwt.EncodeSegment([]byte(`{"alg":"HS256","b64":false}`))
+	if _, err = jwt.Parse(header+`.{"foo":"bar"}.c2ln`, keyFunc); !errors.Is(err, jwt.ErrInvalidB64Header) || !errors.Is(err, jwt.ErrTokenMalformed) {
+		t.Errorf("Parse() error = %v, want %v", err, jwt.ErrInvalidB64Header)
+	}
+}
+
+func TestToken_WithMethod(t *testing.T) {
+	tokenString := signToken(jwt.MapClaims{"foo": "bar"}, jwt.SigningMethodRS256)
+	token, err := jwt.Parse(tokenString, defaultKeyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token.Header["kid"] = "key-1"
+
+	migrated := token.WithMethod(jwt.SigningMethodES256)
+	if migrated.Method != jwt.SigningMethodES256 || migrated.Header["alg"] != "ES256" || migrated.Header["kid"] != "key-1" {
+		t.Fatalf("WithMethod() = %+v, want ES256 token with the original header", migrated)
+	}
+	if migrated.Raw != "" || migrated.Signature != "" || migrated.Valid {
+		t.Errorf("WithMethod() copied the parsing state of the original token")
+	}
+
+	// The original token is not modified
+	if token.Method != jwt.SigningMethodRS256 || token.Header["alg"] != "RS256" {
+		t.Errorf("WithMethod() modified the original token: %+v", token)
+	}
+
+	migratedString, err := migrated.SignedString(jwtTestEC256PrivateKey)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	parsed, err := jwt.Parse(migratedString, ecdsaKeyFunc)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !jwt.ClaimsEqual(parsed.Claims, token.Claims) {
+		t.Errorf("Parse() claims = %v, want %v", parsed.Claims, token.Claims)
+	}
+}
+
+func TestToken_SignedStringAlgMismatch(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
+	token.Header["alg"] = "RS256"
+
+	if _, err := token.SignedString(hmacTestKey); !errors.Is(err, jwt.ErrTokenAlgMismatch) {
+		t.Errorf("SignedString() error = %v, want %v", err, jwt.ErrTokenAlgMismatch)
+	}
+	if _, err := token.SignedJSON(hmacTestKey); !errors.Is(err, jwt.ErrTokenAlgMismatch) {
+		t.Errorf("SignedJSON() error = %v, want %v", err, jwt.ErrTokenAlgMismatch)
+	}
+
+	delete(token.Header, "alg")
+	if _, err := token.SignedString(hmacTestKey); !errors.Is(err, jwt.ErrTokenAlgMismatch) {
+		t.Errorf("SignedString() without alg error = %v, want %v", err, jwt.ErrTokenAlgMismatch)
+	}
+}
+
+func TestToken_ClaimAccessors(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.Claims
+		iss    string
+		sub    string
+		aud    []string
+		jti    string
+		ok     bool
+	}{
+		{
+			name:   "registered claims",
+			claims: &jwt.RegisteredClaims{Issuer: "issuer", Subject: "subject", Audience: jwt.ClaimStrings{"a", "b"}, ID: "id"},
+			iss:    "issuer", sub: "subject", aud: []string{"a", "b"}, jti: "id", ok: true,
+		},
+		{
+			name:   "map claims",
+			claims: jwt.MapClaims{"iss": "issuer", "sub": "subject", "aud": "a", "jti": "id"},
+			iss:    "issuer", sub: "subject", aud: []string{"a"}, jti: "id", ok: true,
+		},
+		{
+			name:   "embedded registered claims",
+			claims: &scopedClaims{RegisteredClaims: jwt.RegisteredClaims{Issuer: "issuer", Subject: "subject", Audience: jwt.ClaimStrings{"a"}, ID: "id"}},
+			iss:    "issuer", sub: "subject", aud: []string{"a"}, jti: "id", ok: true,
+		},
+		{
+			name:   "empty map claims",
+			claims: jwt.MapClaims{},
+		},
+		{
+			name:   "invalid types",
+			claims: jwt.MapClaims{"iss": 1, "sub": true, "aud": []interface{}{1}, "jti": 1.5},
+		},
+		{
+			name:   "claims without getters",
+			claims: &jwt.StandardClaims{Issuer: "issuer"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			token := &jwt.Token{Claims: tc.claims}
+
+			if iss, ok := token.Issuer(); iss != tc.iss || ok != tc.ok {
+				t.Errorf("Issuer() = %q, %v, want %q, %v", iss, ok, tc.iss, tc.ok)
+			}
+			if sub, ok := token.Subject(); sub != tc.sub || ok != tc.ok {
+				t.Errorf("Subject() = %q, %v, want %q, %v", sub, ok, tc.sub, tc.ok)
+			}
+			if aud, ok := token.Audiences(); !reflect.DeepEqual(aud, tc.aud) || ok != tc.ok {
+				t.Errorf("Audiences() = %v, %v, want %v, %v", aud, ok, tc.aud, tc.ok)
+			}
+			if jti, ok := token.ID(); jti != tc.jti || ok != tc.ok {
+				t.Errorf("ID() = %q, %v, want %q, %v", jti, ok, tc.jti, tc.ok)
+			}
+		})
+	}
+}
+
+func TestParseAndBind(t *testing.T) {
+	tokenString := signToken(&scopedClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: "subject"}, Scope: "read"}, jwt.SigningMethodRS256)
+
+	var claims scopedClaims
+	if err := jwt.ParseAndBind(tokenString, defaultKeyFunc, &claims, jwt.WithValidMethods([]string{"RS256"})); err != nil {
+		t.Fatalf("ParseAndBind() error = %v", err)
+	}
+	if claims.Subject != "subject" || claims.Scope != "read" {
+		t.Errorf("ParseAndBind() claims = %+v, want subject and scope", claims)
+	}
+
+	err := jwt.ParseAndBind(tokenString, defaultKeyFunc, &scopedClaims{}, jwt.WithValidMethods([]string{"ES256"}))
+	if !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Errorf("ParseAndBind() error = %v, want %v", err, jwt.ErrTokenSignatureInvalid)
+	}
+
+	err = jwt.ParseAndBind(tokenString, defaultKeyFunc, &scopedClaims{}, jwt.WithIssuer("issuer"))
+	if !errors.Is(err, jwt.ErrTokenInvalidIssuer) {
+		t.Errorf("ParseAndBind() error = %v, want %v", err, jwt.ErrTokenInvalidIssuer)
+	}
+}
+
+func TestToken_SignAndVerify(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		private interface{}
+		public  interface{}
+		err     error
+	}{
+		{"matching key pair", jwt.SigningMethodRS256, jwtTestRSAPrivateKey, jwtTestDefaultKey, nil},
+		{"mismatched key pair", jwt.SigningMethodRS256, jwtTestRSAPrivateKey, &otherKey.PublicKey, rsa.ErrVerification},
+		{"wrong key type", jwt.SigningMethodRS256, jwtTestRSAPrivateKey, jwtTestEC256PublicKey, jwt.ErrInvalidKeyType},
+		{"hmac", jwt.SigningMethodHS256, hmacTestKey, hmacTestKey, nil},
+		{"hmac mismatch", jwt.SigningMethodHS256, hmacTestKey, []byte("other"), jwt.ErrSignatureInvalid},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenString, err := jwt.NewWithClaims(tc.method, jwt.MapClaims{"foo": "bar"}).SignAndVerify(tc.private, tc.public)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("SignAndVerify() error = %v, want %v", err, tc.err)
+			}
+			if tc.err != nil {
+				if tokenString != "" {
+					t.Errorf("SignAndVerify() = %q, want empty string", tokenString)
+				}
+				return
+			}
+			if _, err = jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) { return tc.public, nil }); err != nil {
+				t.Errorf("Parse() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestToken_SignatureBytes(t *testing.T) {
+	token, err := jwt.Parse(signToken(jwt.MapClaims{"foo": "bar"}, jwt.SigningMethodRS256), defaultKeyFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := token.SignatureBytes()
+	if err != nil {
+		t.Fatalf("SignatureBytes() error = %v", err)
+	}
+	if len(sig) != jwtTestDefaultKey.Size() {
+		t.Errorf("SignatureBytes() = %d bytes, want %d", len(sig), jwtTestDefaultKey.Size())
+	}
+	if err = jwt.SigningMethodRS256.Verify(token.SigningInput, jwt.EncodeSegment(sig), jwtTestDefaultKey); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	if _, err = (&jwt.Token{Signature: "!"}).SignatureBytes(); err == nil {
+		t.Errorf("SignatureBytes() expected error")
+	}
+
+	// Signatures of base32 tokens are decoded accordingly
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"}, jwt.WithBase32Segments()).SignedString(hmacTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err = jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) { return hmacTestKey, nil }, jwt.WithBase32Encoding())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig, err = token.SignatureBytes(); err != nil {
+		t.Fatalf("SignatureBytes() error = %v", err)
+	}
+	if err = jwt.SigningMethodHS256.Verify(token.SigningInput, jwt.EncodeSegment(sig), hmacTestKey); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestTokenHash(t *testing.T) {
+	// echo -n "foo" | sha256sum
+	if got, want := jwt.TokenHash("foo"), "LCa0a2j_xo_5m0U8HTBBNBNCLXBkg7-g-YpeiGJm564"; got != want {
+		t.Errorf("TokenHash() = %v, want %v", got, want)
+	}
+
+	tokenString := signToken(jwt.MapClaims{"foo": "bar"}, jwt.SigningMethodRS256)
+	if jwt.TokenHash(tokenString) != jwt.TokenHash(tokenString) || jwt.TokenHash(tokenString) == jwt.TokenHash(tokenString+" ") {
+		t.Errorf("TokenHash() is not stable or not distinct")
+	}
+}
+
+func TestToken_Alg(t *testing.T) {
+	keys := map[jwt.SigningMethod]interface{}{
+		jwt.SigningMethodRS256: jwtTestDefaultKey,
+		jwt.SigningMethodES256: jwtTestEC256PublicKey,
+	}
+
+	for method, key := range keys {
+		// Method is resolved from the header, even without restricting the valid methods
+		token, err := jwt.Parse(signToken(jwt.MapClaims{"foo": "bar"}, method), func(*jwt.Token) (interface{}, error) {
+			return key, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.Method != method || token.Alg() != method.Alg() {
+			t.Errorf("Alg() = %v, want %v", token.Alg(), method.Alg())
+		}
+
+		// Tokens failing verification still report their algorithm
+		token, _ = jwt.Parse(signToken(jwt.MapClaims{"foo": "bar"}, method), func(*jwt.Token) (interface{}, error) {
+			return nil, errors.New("no key")
+		})
+		if token.Alg() != method.Alg() {
+			t.Errorf("Alg() = %v, want %v", token.Alg(), method.Alg())
+		}
+	}
+
+	if alg := (&jwt.Token{Header: map[string]interface{}{"alg": "XX"}}).Alg(); alg != "XX" {
+		t.Errorf("Alg() = %v, want XX", alg)
+	}
+	if alg := (&jwt.Token{}).Alg(); alg != "" {
+		t.Errorf("Alg() = %v, want empty", alg)
+	}
+}
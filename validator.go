@@ -0,0 +1,394 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ValidateClaims validates claims independently of parsing a token, e.g. if the token was
+// transported and decoded by other means. It performs the same validation as the Parser: the
+// claims' own Valid method (which covers "exp", "iat" and "nbf"), followed by the checks that are
+// configured using options such as WithIssuer and WithAudience. Options that only influence the
+// parsing itself have no effect.
+func ValidateClaims(claims Claims, options ...ParserOption) error {
+	if vErr := NewParser(options...).validateClaims(claims); !vErr.valid() {
+		return vErr
+	}
+
+	return nil
+}
+
+// validateClaims validates claims using their Valid method as well as the claim checks configured
+// in the parser. The returned ValidationError has no error flags set if the claims are valid.
+func (p *Parser) validateClaims(claims Claims) *ValidationError {
+	vErr := &ValidationError{}
+
+	if err := claims.Valid(); err != nil {
+		// If the Claims Valid returned an error, check if it is a validation error,
+		// If it was another error type, create a ValidationError with a generic ClaimsInvalid flag set
+		if e, ok := err.(*ValidationError); !ok {
+			vErr = &ValidationError{Inner: err, Errors: ValidationErrorClaimsInvalid}
+		} else {
+			vErr = e
+		}
+	}
+
+	if p.observer != nil {
+		p.observeValid(vErr)
+	}
+
+	if !p.hasClaimChecks() {
+		return vErr
+	}
+
+	m, err := toMapClaims(claims)
+	if err != nil {
+		p.check(vErr, "claims", false, err, ValidationErrorClaimsInvalid)
+		return vErr
+	}
+
+	if len(p.issuers) > 0 {
+		p.check(vErr, "iss", verifyIssuers(m, p.issuers), ErrTokenInvalidIssuer, ValidationErrorIssuer)
+	}
+
+	if p.verifyAudience {
+		p.check(vErr, "aud", p.verifyAud(m), ErrTokenInvalidAudience, ValidationErrorAudience)
+	}
+
+	if p.nonEmptyAudience {
+		p.check(vErr, "aud", verifyAudNonEmpty(m), ErrTokenInvalidAudience, ValidationErrorAudience)
+	}
+
+	if p.exclusiveAudience != nil {
+		p.check(vErr, "aud", p.verifyAudExclusive(m), ErrTokenInvalidAudience, ValidationErrorAudience)
+	}
+
+	if p.requireJTI {
+		ok, err := verifyJTI(m)
+		p.check(vErr, "jti", ok, err, ValidationErrorId)
+	}
+
+	if p.verifyNonce {
+		ok, err := verifyStringClaim(m, "nonce", p.nonce, ErrTokenInvalidNonce)
+		p.check(vErr, "nonce", ok, err, ValidationErrorClaimsInvalid)
+	}
+
+	if p.verifyClientID {
+		ok, err := verifyStringClaim(m, "client_id", p.clientID, ErrTokenInvalidClientID)
+		p.check(vErr, "client_id", ok, err, ValidationErrorClaimsInvalid)
+	}
+
+	if p.maxTokenAge != 0 {
+		ok, err := verifyMaxAge(m, "iat", p.maxTokenAge)
+		p.check(vErr, "age", ok, err, ValidationErrorClaimsInvalid)
+	}
+
+	if !p.minIssuedAt.IsZero() {
+		ok, err := verifyMinIssuedAt(m, p.minIssuedAt)
+		p.check(vErr, "iat", ok, err, ValidationErrorClaimsInvalid)
+	}
+
+	if p.maxExpiry != 0 {
+		ok, err := verifyMaxExpiry(m, p.maxExpiry)
+		p.check(vErr, "expiry", ok, err, ValidationErrorClaimsInvalid)
+	}
+
+	if p.requireTimeConstraint {
+		ok, err := verifyTimeConstraint(m)
+		p.check(vErr, "time_constraint", ok, err, ValidationErrorClaimsInvalid)
+	}
+
+	if p.requireExpiration {
+		ok, err := verifyRequiredDate(m, "exp")
+		p.check(vErr, "exp", ok, err, ValidationErrorClaimsInvalid)
+	}
+
+	for _, c := range p.timeClaims {
+		ok, err := verifyMaxAge(m, c.name, c.maxAge)
+		p.check(vErr, c.name, ok, err, ValidationErrorClaimsInvalid)
+	}
+
+	return vErr
+}
+
+// hasClaimChecks reports whether any claim checks are configured, in addition to the claims'
+// own Valid method.
+func (p *Parser) hasClaimChecks() bool {
+	return len(p.issuers) > 0 || p.verifyAudience || p.nonEmptyAudience || p.exclusiveAudience != nil ||
+		p.requireJTI || p.verifyNonce || p.verifyClientID || p.maxTokenAge != 0 || !p.minIssuedAt.IsZero() || p.maxExpiry != 0 || p.requireTimeConstraint || p.requireExpiration ||
+		len(p.timeClaims) > 0
+}
+
+// check records the outcome of a single claim check. If the check failed, inner and flag are
+// added to vErr. The outcome is also reported to the validation observer, if any.
+func (p *Parser) check(vErr *ValidationError, name string, ok bool, inner error, flag uint32) {
+	if p.observer != nil {
+		if ok {
+			p.observer(name, true, nil)
+		} else {
+			p.observer(name, false, inner)
+		}
+	}
+
+	if !ok {
+		vErr.Inner = inner
+		vErr.Errors |= flag
+	}
+}
+
+// observeValid reports the outcome of the claims' Valid method to the validation observer,
+// split up into the "exp", "iat" and "nbf" checks. Any other failure is reported as "claims".
+func (p *Parser) observeValid(vErr *ValidationError) {
+	timeChecks := []struct {
+		name string
+		flag uint32
+		err  error
+	}{
+		{"exp", ValidationErrorExpired, ErrTokenExpired},
+		{"iat", ValidationErrorIssuedAt, ErrTokenUsedBeforeIssued},
+		{"nbf", ValidationErrorNotValidYet, ErrTokenNotValidYet},
+	}
+
+	remaining := vErr.Errors
+	for _, c := range timeChecks {
+		if vErr.Errors&c.flag != 0 {
+			p.observer(c.name, false, c.err)
+		} else {
+			p.observer(c.name, true, nil)
+		}
+		remaining &^= c.flag
+	}
+
+	if remaining != 0 {
+		p.observer("claims", false, vErr)
+	}
+}
+
+// toMapClaims returns a MapClaims view of claims, so that individual claims can be inspected
+// regardless of the concrete claims type. Claims other than MapClaims are converted using a JSON
+// round trip, which also honors custom JSON marshalling of the claims type.
+func toMapClaims(claims Claims) (MapClaims, error) {
+	if m, ok := claims.(MapClaims); ok {
+		return m, nil
+	}
+
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	m := MapClaims{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err = dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// verifyAudNonEmpty checks that the "aud" claim does not contain empty entries.
+func verifyAudNonEmpty(m MapClaims) bool {
+	aud, ok := m.audience()
+	if !ok {
+		return false
+	}
+
+	for _, a := range aud {
+		if a == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// verifyAud checks that the "aud" claim contains the audience configured with WithAudience.
+func (p *Parser) verifyAud(m MapClaims) bool {
+	aud, ok := p.audienceClaim(m)
+	if !ok {
+		return false
+	}
+
+	return verifyAud(aud, p.normalizeAudience(p.audience), true)
+}
+
+// verifyAudExclusive checks that all entries of the "aud" claim are contained in the audiences
+// configured with WithExclusiveAudience.
+func (p *Parser) verifyAudExclusive(m MapClaims) bool {
+	aud, ok := p.audienceClaim(m)
+	if !ok {
+		return false
+	}
+
+	for _, a := range aud {
+		found := false
+		for _, b := range p.exclusiveAudience {
+			if a == p.normalizeAudience(b) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// verifyJTI checks that the "jti" claim is present and a non-empty string.
+func verifyJTI(m MapClaims) (bool, error) {
+	v, ok := m["jti"]
+	if !ok {
+		return false, fmt.Errorf("%w: jti", ErrTokenRequiredClaimMissing)
+	}
+	jti, ok := v.(string)
+	if !ok {
+		return false, fmt.Errorf("%w: jti", ErrInvalidType)
+	}
+	if jti == "" {
+		return false, ErrTokenInvalidId
+	}
+
+	return true, nil
+}
+
+// verifyStringClaim checks that the string claim name is present and equals expected. Otherwise,
+// invalid is returned, unless the claim is missing or not a string.
+func verifyStringClaim(m MapClaims, name, expected string, invalid error) (bool, error) {
+	v, ok := m[name]
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrTokenRequiredClaimMissing, name)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrInvalidType, name)
+	}
+	if s == "" || subtle.ConstantTimeCompare([]byte(s), []byte(expected)) == 0 {
+		return false, invalid
+	}
+
+	return true, nil
+}
+
+// verifyMaxAge checks that the time claim name is present and not older than maxAge.
+func verifyMaxAge(m MapClaims, name string, maxAge time.Duration) (bool, error) {
+	t, ok := m.date(name)
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrInvalidType, name)
+	}
+	if t == nil {
+		return false, fmt.Errorf("%w: %s", ErrTokenRequiredClaimMissing, name)
+	}
+
+	if age := TimeFunc().Sub(*t); age > maxAge {
+		return false, fmt.Errorf("%w: %s is %s ago", ErrTokenTooOld, name, age.Truncate(time.Second))
+	}
+
+	return true, nil
+}
+
+// verifyMinIssuedAt checks that the "iat" claim is present and not before cutoff.
+func verifyMinIssuedAt(m MapClaims, cutoff time.Time) (bool, error) {
+	t, ok := m.date("iat")
+	if !ok {
+		return false, fmt.Errorf("%w: iat", ErrInvalidType)
+	}
+	if t == nil {
+		return false, fmt.Errorf("%w: iat", ErrTokenRequiredClaimMissing)
+	}
+
+	if t.Before(cutoff) {
+		return false, fmt.Errorf("%w: iat is %s", ErrTokenIssuedBeforeCutoff, t.UTC().Format(time.RFC3339))
+	}
+
+	return true, nil
+}
+
+// verifyMaxExpiry checks that the "exp" claim, if present, is at most maxExpiry in the future.
+func verifyMaxExpiry(m MapClaims, maxExpiry time.Duration) (bool, error) {
+	t, ok := m.date("exp")
+	if !ok {
+		return false, fmt.Errorf("%w: exp", ErrInvalidType)
+	}
+
+	if t != nil {
+		if d := t.Sub(TimeFunc()); d > maxExpiry {
+			return false, fmt.Errorf("%w: exp is in %s", ErrTokenExpiryTooFar, d.Truncate(time.Second))
+		}
+	}
+
+	return true, nil
+}
+
+// verifyRequiredDate checks that the time claim name is present.
+func verifyRequiredDate(m MapClaims, name string) (bool, error) {
+	t, ok := m.date(name)
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrInvalidType, name)
+	}
+	if t == nil {
+		return false, fmt.Errorf("%w: %s", ErrTokenRequiredClaimMissing, name)
+	}
+
+	return true, nil
+}
+
+// verifyTimeConstraint checks that at least one of the "exp" and "nbf" claims is present.
+func verifyTimeConstraint(m MapClaims) (bool, error) {
+	exp, ok := m.date("exp")
+	if !ok {
+		return false, fmt.Errorf("%w: exp", ErrInvalidType)
+	}
+	nbf, ok := m.date("nbf")
+	if !ok {
+		return false, fmt.Errorf("%w: nbf", ErrInvalidType)
+	}
+
+	if exp == nil && nbf == nil {
+		return false, fmt.Errorf("%w: exp or nbf", ErrTokenRequiredClaimMissing)
+	}
+
+	return true, nil
+}
+
+// verifyIssuers checks that the "iss" claim matches one of issuers.
+func verifyIssuers(m MapClaims, issuers []string) bool {
+	for _, iss := range issuers {
+		if m.VerifyIssuer(iss, true) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// audienceClaim returns the "aud" claim like MapClaims.audience, with its entries normalized using
+// normalizeAudience.
+func (p *Parser) audienceClaim(m MapClaims) ([]string, bool) {
+	aud, ok := m.audience()
+	if !ok || !p.normalizeAudienceURLs {
+		return aud, ok
+	}
+
+	normalized := make([]string, len(aud))
+	for i, a := range aud {
+		normalized[i] = p.normalizeAudience(a)
+	}
+
+	return normalized, true
+}
+
+// normalizeAudience removes trailing slashes from aud, if WithAudienceURLNormalization is set.
+func (p *Parser) normalizeAudience(aud string) string {
+	if !p.normalizeAudienceURLs {
+		return aud
+	}
+
+	return strings.TrimRight(aud, "/")
+}
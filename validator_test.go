@@ -0,0 +1,487 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestValidateClaims(t *testing.T) {
+	type idTokenClaims struct {
+		Nonce string `json:"nonce,omitempty"`
+		jwt.RegisteredClaims
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-time.Hour)
+	timeClaims := []jwt.ParserOption{
+		jwt.WithTimeClaim("auth_time", 5*time.Minute),
+		jwt.WithTimeClaim("pwd_time", 24*time.Hour),
+	}
+	apiAudiences := []string{"api", "api-v2"}
+
+	tests := []struct {
+		name    string
+		claims  jwt.Claims
+		options []jwt.ParserOption
+		err     error
+	}{
+		{
+			name:   "map claims without options",
+			claims: jwt.MapClaims{"foo": "bar"},
+		},
+		{
+			name:   "map claims expired",
+			claims: jwt.MapClaims{"exp": float64(now.Unix() - 100)},
+			err:    jwt.ErrTokenExpired,
+		},
+		{
+			name:   "registered claims not valid yet",
+			claims: &jwt.RegisteredClaims{NotBefore: jwt.NewNumericDate(now.Add(time.Minute))},
+			err:    jwt.ErrTokenNotValidYet,
+		},
+		{
+			name:    "issuer matching",
+			claims:  jwt.MapClaims{"iss": "issuer"},
+			options: []jwt.ParserOption{jwt.WithIssuer("issuer")},
+		},
+		{
+			name:    "issuer mismatch",
+			claims:  &jwt.RegisteredClaims{Issuer: "other"},
+			options: []jwt.ParserOption{jwt.WithIssuer("issuer")},
+			err:     jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			name:    "issuer missing",
+			claims:  jwt.MapClaims{},
+			options: []jwt.ParserOption{jwt.WithIssuer("issuer")},
+			err:     jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			name:    "one of several issuers",
+			claims:  &jwt.RegisteredClaims{Issuer: "idp-2"},
+			options: []jwt.ParserOption{jwt.WithIssuers([]string{"idp-1", "idp-2"})},
+		},
+		{
+			name:    "none of several issuers",
+			claims:  jwt.MapClaims{"iss": "idp-3"},
+			options: []jwt.ParserOption{jwt.WithIssuers([]string{"idp-1", "idp-2"})},
+			err:     jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			name:    "issuer missing with several issuers",
+			claims:  jwt.MapClaims{},
+			options: []jwt.ParserOption{jwt.WithIssuers([]string{"idp-1", "idp-2"})},
+			err:     jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			name:    "audience contained",
+			claims:  &jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"a", "b"}},
+			options: []jwt.ParserOption{jwt.WithAudience("b")},
+		},
+		{
+			name:    "audience mismatch",
+			claims:  jwt.MapClaims{"aud": "a"},
+			options: []jwt.ParserOption{jwt.WithAudience("b")},
+			err:     jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "empty audience never matches",
+			claims:  jwt.MapClaims{"aud": []interface{}{"", "a"}},
+			options: []jwt.ParserOption{jwt.WithAudience("")},
+			err:     jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "empty audience entry rejected",
+			claims:  &jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"a", ""}},
+			options: []jwt.ParserOption{jwt.WithAudience("a"), jwt.WithNonEmptyAudience()},
+			err:     jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "non-empty audience entries",
+			claims:  &jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"a", "b"}},
+			options: []jwt.ParserOption{jwt.WithNonEmptyAudience()},
+		},
+		{
+			name: "audience and expiry",
+			claims: &jwt.RegisteredClaims{
+				Audience:  jwt.ClaimStrings{"a"},
+				ExpiresAt: jwt.NewNumericDate(now.Add(-time.Minute)),
+			},
+			options: []jwt.ParserOption{jwt.WithAudience("b")},
+			err:     jwt.ErrTokenExpired,
+		},
+		{
+			name:    "recently issued",
+			claims:  &jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(now.Add(-time.Minute))},
+			options: []jwt.ParserOption{jwt.WithMaxTokenAge(time.Hour)},
+		},
+		{
+			name:    "too old despite valid exp",
+			claims:  jwt.MapClaims{"iat": float64(now.Add(-2 * time.Hour).Unix()), "exp": float64(now.Add(time.Hour).Unix())},
+			options: []jwt.ParserOption{jwt.WithMaxTokenAge(time.Hour)},
+			err:     jwt.ErrTokenTooOld,
+		},
+		{
+			name:    "iat missing with max age",
+			claims:  jwt.MapClaims{"exp": float64(now.Add(time.Hour).Unix())},
+			options: []jwt.ParserOption{jwt.WithMaxTokenAge(time.Hour)},
+			err:     jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "iat of invalid type with max age",
+			claims:  jwt.MapClaims{"iat": "yesterday"},
+			options: []jwt.ParserOption{jwt.WithMaxTokenAge(time.Hour)},
+			err:     jwt.ErrInvalidType,
+		},
+		{
+			name:    "issued after cutoff",
+			claims:  &jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(cutoff.Add(time.Minute))},
+			options: []jwt.ParserOption{jwt.WithMinIssuedAt(cutoff)},
+		},
+		{
+			name:    "issued before cutoff despite valid exp",
+			claims:  jwt.MapClaims{"iat": float64(cutoff.Add(-time.Minute).Unix()), "exp": float64(now.Add(time.Hour).Unix())},
+			options: []jwt.ParserOption{jwt.WithMinIssuedAt(cutoff)},
+			err:     jwt.ErrTokenIssuedBeforeCutoff,
+		},
+		{
+			name:    "iat missing with cutoff",
+			claims:  jwt.MapClaims{"exp": float64(now.Add(time.Hour).Unix())},
+			options: []jwt.ParserOption{jwt.WithMinIssuedAt(cutoff)},
+			err:     jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "iat of invalid type with cutoff",
+			claims:  jwt.MapClaims{"iat": "yesterday"},
+			options: []jwt.ParserOption{jwt.WithMinIssuedAt(cutoff)},
+			err:     jwt.ErrInvalidType,
+		},
+		{
+			name:    "fresh time claims",
+			claims:  jwt.MapClaims{"auth_time": float64(now.Add(-time.Minute).Unix()), "pwd_time": float64(now.Add(-time.Hour).Unix())},
+			options: timeClaims,
+		},
+		{
+			name:    "stale time claim",
+			claims:  jwt.MapClaims{"auth_time": float64(now.Add(-time.Hour).Unix()), "pwd_time": float64(now.Add(-time.Hour).Unix())},
+			options: timeClaims,
+			err:     jwt.ErrTokenTooOld,
+		},
+		{
+			name:    "time claims as json.Number",
+			claims:  jwt.MapClaims{"auth_time": json.Number(fmt.Sprint(now.Unix())), "pwd_time": json.Number(fmt.Sprint(now.Unix()))},
+			options: timeClaims,
+		},
+		{
+			name:    "time claim missing",
+			claims:  jwt.MapClaims{"auth_time": float64(now.Unix())},
+			options: timeClaims,
+			err:     jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "jti in registered claims",
+			claims:  &jwt.RegisteredClaims{ID: "f3b2"},
+			options: []jwt.ParserOption{jwt.WithRequireJTI()},
+		},
+		{
+			name:    "jti in map claims",
+			claims:  jwt.MapClaims{"jti": "f3b2"},
+			options: []jwt.ParserOption{jwt.WithRequireJTI()},
+		},
+		{
+			name:    "jti missing",
+			claims:  &jwt.RegisteredClaims{Subject: "foo"},
+			options: []jwt.ParserOption{jwt.WithRequireJTI()},
+			err:     jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "jti empty",
+			claims:  jwt.MapClaims{"jti": ""},
+			options: []jwt.ParserOption{jwt.WithRequireJTI()},
+			err:     jwt.ErrTokenInvalidId,
+		},
+		{
+			name:    "jti of invalid type",
+			claims:  jwt.MapClaims{"jti": 42},
+			options: []jwt.ParserOption{jwt.WithRequireJTI()},
+			err:     jwt.ErrInvalidType,
+		},
+		{
+			name:    "nonce in struct claims",
+			claims:  &idTokenClaims{Nonce: "n-0S6_WzA2Mj"},
+			options: []jwt.ParserOption{jwt.WithNonce("n-0S6_WzA2Mj")},
+		},
+		{
+			name:    "nonce in map claims",
+			claims:  jwt.MapClaims{"nonce": "n-0S6_WzA2Mj"},
+			options: []jwt.ParserOption{jwt.WithNonce("n-0S6_WzA2Mj")},
+		},
+		{
+			name:    "nonce missing",
+			claims:  &idTokenClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: "foo"}},
+			options: []jwt.ParserOption{jwt.WithNonce("n-0S6_WzA2Mj")},
+			err:     jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "nonce mismatch",
+			claims:  jwt.MapClaims{"nonce": "n-0S6_WzA2Mk"},
+			options: []jwt.ParserOption{jwt.WithNonce("n-0S6_WzA2Mj")},
+			err:     jwt.ErrTokenInvalidNonce,
+		},
+		{
+			name:    "nonce of invalid type",
+			claims:  jwt.MapClaims{"nonce": 42},
+			options: []jwt.ParserOption{jwt.WithNonce("n-0S6_WzA2Mj")},
+			err:     jwt.ErrInvalidType,
+		},
+		{
+			name:    "empty nonce never matches",
+			claims:  jwt.MapClaims{"nonce": ""},
+			options: []jwt.ParserOption{jwt.WithNonce("")},
+			err:     jwt.ErrTokenInvalidNonce,
+		},
+		{
+			name:    "client_id matching",
+			claims:  jwt.MapClaims{"client_id": "s6BhdRkqt3", "sub": "foo"},
+			options: []jwt.ParserOption{jwt.WithClientID("s6BhdRkqt3")},
+		},
+		{
+			name:    "client_id missing",
+			claims:  &jwt.RegisteredClaims{Subject: "foo"},
+			options: []jwt.ParserOption{jwt.WithClientID("s6BhdRkqt3")},
+			err:     jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "client_id of other client",
+			claims:  jwt.MapClaims{"client_id": "s6BhdRkqt4"},
+			options: []jwt.ParserOption{jwt.WithClientID("s6BhdRkqt3")},
+			err:     jwt.ErrTokenInvalidClientID,
+		},
+		{
+			name:    "client_id empty",
+			claims:  jwt.MapClaims{"client_id": ""},
+			options: []jwt.ParserOption{jwt.WithClientID("s6BhdRkqt3")},
+			err:     jwt.ErrTokenInvalidClientID,
+		},
+		{
+			name:    "client_id of invalid type",
+			claims:  jwt.MapClaims{"client_id": []string{"s6BhdRkqt3"}},
+			options: []jwt.ParserOption{jwt.WithClientID("s6BhdRkqt3")},
+			err:     jwt.ErrInvalidType,
+		},
+		{
+			name:    "exp within max expiry",
+			claims:  &jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))},
+			options: []jwt.ParserOption{jwt.WithMaxExpiry(24 * time.Hour)},
+		},
+		{
+			name:    "exp missing with max expiry",
+			claims:  jwt.MapClaims{"sub": "foo"},
+			options: []jwt.ParserOption{jwt.WithMaxExpiry(24 * time.Hour)},
+		},
+		{
+			name:    "exp too far in the future",
+			claims:  &jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(now.Add(10 * 365 * 24 * time.Hour))},
+			options: []jwt.ParserOption{jwt.WithMaxExpiry(24 * time.Hour)},
+			err:     jwt.ErrTokenExpiryTooFar,
+		},
+		{
+			name:    "exp of invalid type with max expiry",
+			claims:  jwt.MapClaims{"exp": "tomorrow"},
+			options: []jwt.ParserOption{jwt.WithMaxExpiry(24 * time.Hour)},
+			err:     jwt.ErrInvalidType,
+		},
+		{
+			name:    "time constraint by exp",
+			claims:  &jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))},
+			options: []jwt.ParserOption{jwt.WithTimeConstraintRequired()},
+		},
+		{
+			name:    "time constraint by nbf",
+			claims:  jwt.MapClaims{"nbf": float64(now.Add(-time.Minute).Unix())},
+			options: []jwt.ParserOption{jwt.WithTimeConstraintRequired()},
+		},
+		{
+			name:    "neither exp nor nbf",
+			claims:  &jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(now)},
+			options: []jwt.ParserOption{jwt.WithTimeConstraintRequired()},
+			err:     jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "nbf of invalid type with time constraint",
+			claims:  jwt.MapClaims{"nbf": "now"},
+			options: []jwt.ParserOption{jwt.WithTimeConstraintRequired()},
+			err:     jwt.ErrInvalidType,
+		},
+		{
+			name:    "exp required and set",
+			claims:  &jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))},
+			options: []jwt.ParserOption{jwt.WithExpirationRequired()},
+		},
+		{
+			name:    "exp required but nbf only",
+			claims:  jwt.MapClaims{"nbf": float64(now.Add(-time.Minute).Unix())},
+			options: []jwt.ParserOption{jwt.WithExpirationRequired()},
+			err:     jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "exp required but of invalid type",
+			claims:  jwt.MapClaims{"exp": "tomorrow"},
+			options: []jwt.ParserOption{jwt.WithExpirationRequired()},
+			err:     jwt.ErrInvalidType,
+		},
+		{
+			name:    "single exclusive audience",
+			claims:  &jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"api"}},
+			options: []jwt.ParserOption{jwt.WithExclusiveAudience(apiAudiences)},
+		},
+		{
+			name:    "all audiences exclusive",
+			claims:  jwt.MapClaims{"aud": []interface{}{"api-v2", "api"}},
+			options: []jwt.ParserOption{jwt.WithExclusiveAudience(apiAudiences)},
+		},
+		{
+			name:    "aud missing with exclusive audience",
+			claims:  jwt.MapClaims{"sub": "foo"},
+			options: []jwt.ParserOption{jwt.WithExclusiveAudience(apiAudiences)},
+		},
+		{
+			name:    "unexpected audience",
+			claims:  &jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"api", "billing"}},
+			options: []jwt.ParserOption{jwt.WithExclusiveAudience(apiAudiences)},
+			err:     jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "aud of invalid type with exclusive audience",
+			claims:  jwt.MapClaims{"aud": []interface{}{"api", 1}},
+			options: []jwt.ParserOption{jwt.WithExclusiveAudience(apiAudiences)},
+			err:     jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "no exclusive audience allowed",
+			claims:  jwt.MapClaims{"aud": "api"},
+			options: []jwt.ParserOption{jwt.WithExclusiveAudience(nil)},
+			err:     jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "audience URL exact match",
+			claims:  jwt.MapClaims{"aud": "https://api.example.com"},
+			options: []jwt.ParserOption{jwt.WithAudience("https://api.example.com")},
+		},
+		{
+			name:    "audience URL with trailing slash without normalization",
+			claims:  jwt.MapClaims{"aud": "https://api.example.com/"},
+			options: []jwt.ParserOption{jwt.WithAudience("https://api.example.com")},
+			err:     jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "audience URL with trailing slash",
+			claims:  jwt.MapClaims{"aud": "https://api.example.com/"},
+			options: []jwt.ParserOption{jwt.WithAudience("https://api.example.com"), jwt.WithAudienceURLNormalization()},
+		},
+		{
+			name:    "expected audience URL with trailing slash",
+			claims:  &jwt.RegisteredClaims{Audience: jwt.ClaimStrings{"other", "https://api.example.com"}},
+			options: []jwt.ParserOption{jwt.WithAudience("https://api.example.com/"), jwt.WithAudienceURLNormalization()},
+		},
+		{
+			name:    "audience URL with different path",
+			claims:  jwt.MapClaims{"aud": "https://api.example.com/v2/"},
+			options: []jwt.ParserOption{jwt.WithAudience("https://api.example.com"), jwt.WithAudienceURLNormalization()},
+			err:     jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "exclusive audience URLs",
+			claims:  jwt.MapClaims{"aud": []interface{}{"https://api.example.com/", "https://billing.example.com"}},
+			options: []jwt.ParserOption{jwt.WithExclusiveAudience([]string{"https://api.example.com", "https://billing.example.com/"}), jwt.WithAudienceURLNormalization()},
+		},
+		{
+			name:    "exclusive audience URLs without normalization",
+			claims:  jwt.MapClaims{"aud": []interface{}{"https://api.example.com/"}},
+			options: []jwt.ParserOption{jwt.WithExclusiveAudience([]string{"https://api.example.com"})},
+			err:     jwt.ErrTokenInvalidAudience,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := jwt.ValidateClaims(tc.claims, tc.options...)
+			if tc.err == nil && err != nil {
+				t.Errorf("ValidateClaims() unexpected error = %v", err)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Errorf("ValidateClaims() error = %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestParser_ParseWithIssuerAndAudience(t *testing.T) {
+	tokenString := signToken(jwt.MapClaims{"iss": "issuer", "aud": []string{"a", "b"}}, jwt.SigningMethodRS256)
+
+	if _, err := jwt.Parse(tokenString, defaultKeyFunc, jwt.WithIssuer("issuer"), jwt.WithAudience("a")); err != nil {
+		t.Errorf("Parse() unexpected error = %v", err)
+	}
+
+	_, err := jwt.Parse(tokenString, defaultKeyFunc, jwt.WithIssuer("other"))
+	if !errors.Is(err, jwt.ErrTokenInvalidIssuer) {
+		t.Errorf("Parse() error = %v, want %v", err, jwt.ErrTokenInvalidIssuer)
+	}
+
+	_, err = jwt.Parse(tokenString, defaultKeyFunc, jwt.WithAudience("c"))
+	if !errors.Is(err, jwt.ErrTokenInvalidAudience) {
+		t.Errorf("Parse() error = %v, want %v", err, jwt.ErrTokenInvalidAudience)
+	}
+}
+
+func TestValidateClaims_Observer(t *testing.T) {
+	type outcome struct {
+		check string
+		ok    bool
+		err   error
+	}
+
+	var outcomes []outcome
+	observer := jwt.WithValidationObserver(func(check string, ok bool, err error) {
+		outcomes = append(outcomes, outcome{check, ok, err})
+	})
+
+	claims := &jwt.RegisteredClaims{
+		Issuer:    "issuer",
+		Audience:  jwt.ClaimStrings{"a"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+	}
+
+	err := jwt.ValidateClaims(claims, observer, jwt.WithIssuer("issuer"), jwt.WithAudience("b"))
+	if !errors.Is(err, jwt.ErrTokenExpired) || !errors.Is(err, jwt.ErrTokenInvalidAudience) {
+		t.Fatalf("ValidateClaims() error = %v, want expired and invalid audience", err)
+	}
+
+	want := []outcome{
+		{"exp", false, jwt.ErrTokenExpired},
+		{"iat", true, nil},
+		{"nbf", true, nil},
+		{"iss", true, nil},
+		{"aud", false, jwt.ErrTokenInvalidAudience},
+	}
+	if len(outcomes) != len(want) {
+		t.Fatalf("observer called with %v, want %v", outcomes, want)
+	}
+	for i := range want {
+		if outcomes[i] != want[i] {
+			t.Errorf("observer call %d = %v, want %v", i, outcomes[i], want[i])
+		}
+	}
+
+	// The observer must not change the outcome
+	claims.ExpiresAt = nil
+	claims.Audience = jwt.ClaimStrings{"b"}
+	if err = jwt.ValidateClaims(claims, observer, jwt.WithAudience("b")); err != nil {
+		t.Errorf("ValidateClaims() unexpected error = %v", err)
+	}
+}